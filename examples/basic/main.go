@@ -19,6 +19,7 @@ var (
 	language   = flag.String("lang", "eng", "OCR language")
 	timeout    = flag.Duration("timeout", 30*time.Second, "OCR timeout duration")
 	outputType = flag.String("output", "text", "Output type: text, tsv, hocr, pdf")
+	pdfPath    = flag.String("pdf-out", "output.pdf", "File to write when -output=pdf")
 )
 
 func main() {
@@ -74,11 +75,18 @@ func main() {
 		fmt.Printf("HOCR Result:\n%s\n", output)
 
 	case "pdf":
-		output, err := client.ImageToExtension(img, *language, "pdf")
+		f, err := os.Create(*pdfPath)
+		if err != nil {
+			log.Fatalf("Failed to create PDF file: %v", err)
+		}
+		err = client.WritePDF(f, img, *language, tesseract.PDFOptions{})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
 		if err != nil {
 			log.Fatalf("PDF creation failed: %v", err)
 		}
-		fmt.Printf("PDF Result:\n%s\n", output)
+		fmt.Printf("PDF written to %s\n", *pdfPath)
 
 	case "boxes":
 		boxes, err := client.ImageToBoxes(img, *language)
@@ -0,0 +1,26 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bytes"
+	"context"
+	"image"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract/alto"
+)
+
+// ImageToALTO performs OCR and returns the ALTO XML output as a document.
+// It requires Tesseract 4.1 or newer.
+func (c *Client) ImageToALTO(img image.Image, lang string, opts ...Options) (*alto.Document, error) {
+	return c.ImageToALTOContext(context.Background(), img, lang, opts...)
+}
+
+// ImageToALTOContext is like ImageToALTO but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToALTOContext(ctx context.Context, img image.Image, lang string, opts ...Options) (*alto.Document, error) {
+	out, err := c.extension(ctx, ImageInput(img), lang, "xml", opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return alto.Decode(bytes.NewReader(out))
+}
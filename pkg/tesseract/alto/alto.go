@@ -0,0 +1,346 @@
+// Package alto provides encoding/xml types for ALTO v3 and v4 documents
+// as produced by Tesseract, so they can be inspected, edited and written
+// back out
+package alto
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ALTO namespaces understood by this package
+const (
+	NamespaceV3 = "http://www.loc.gov/standards/alto/ns-v3#"
+	NamespaceV4 = "http://www.loc.gov/standards/alto/ns-v4#"
+)
+
+// namespaceXSI is the XML Schema instance namespace of the root
+// xsi:schemaLocation attribute
+const namespaceXSI = "http://www.w3.org/2001/XMLSchema-instance"
+
+// ErrUnsupportedVersion indicates a document in an ALTO namespace other
+// than v3 or v4
+var ErrUnsupportedVersion = errors.New("alto: unsupported ALTO version")
+
+// Document is the root alto element. XMLName.Space holds the namespace
+// and SchemaLocation the xsi:schemaLocation attribute, which Encode both
+// writes back out.
+type Document struct {
+	XMLName        xml.Name `xml:"alto"`
+	SchemaLocation string   `xml:"-"`
+
+	Description *Description `xml:"Description,omitempty"`
+	Styles      *Styles      `xml:"Styles,omitempty"`
+	Tags        *Tags        `xml:"Tags,omitempty"`
+	Layout      Layout       `xml:"Layout"`
+}
+
+// Description holds the document metadata
+type Description struct {
+	MeasurementUnit        string                  `xml:"MeasurementUnit,omitempty"`
+	SourceImageInformation *SourceImageInformation `xml:"sourceImageInformation,omitempty"`
+	OCRProcessing          []Processing            `xml:"OCRProcessing,omitempty"`
+	Processing             []Processing            `xml:"Processing,omitempty"`
+}
+
+// SourceImageInformation identifies the image the text was recognised from
+type SourceImageInformation struct {
+	FileName string `xml:"fileName,omitempty"`
+}
+
+// Processing describes a processing step; v3 documents use it as
+// OCRProcessing, v4 documents as Processing
+type Processing struct {
+	ID                 string              `xml:"ID,attr,omitempty"`
+	OCRProcessingStep  *ProcessingStep     `xml:"ocrProcessingStep,omitempty"`
+	ProcessingSoftware *ProcessingSoftware `xml:"processingSoftware,omitempty"`
+}
+
+// ProcessingStep wraps the software used in an OCRProcessing entry
+type ProcessingStep struct {
+	ProcessingSoftware *ProcessingSoftware `xml:"processingSoftware,omitempty"`
+}
+
+// ProcessingSoftware names the software that produced the document
+type ProcessingSoftware struct {
+	SoftwareCreator string `xml:"softwareCreator,omitempty"`
+	SoftwareName    string `xml:"softwareName,omitempty"`
+	SoftwareVersion string `xml:"softwareVersion,omitempty"`
+}
+
+// Styles holds the text and paragraph styles that STYLEREFS attributes
+// refer to
+type Styles struct {
+	TextStyles      []TextStyle      `xml:"TextStyle,omitempty"`
+	ParagraphStyles []ParagraphStyle `xml:"ParagraphStyle,omitempty"`
+}
+
+// TextStyle describes a font
+type TextStyle struct {
+	ID         string  `xml:"ID,attr"`
+	FontFamily string  `xml:"FONTFAMILY,attr,omitempty"`
+	FontType   string  `xml:"FONTTYPE,attr,omitempty"`
+	FontWidth  string  `xml:"FONTWIDTH,attr,omitempty"`
+	FontSize   float64 `xml:"FONTSIZE,attr"`
+	FontColor  string  `xml:"FONTCOLOR,attr,omitempty"`
+	FontStyle  string  `xml:"FONTSTYLE,attr,omitempty"`
+}
+
+// ParagraphStyle describes the alignment and spacing of a paragraph
+type ParagraphStyle struct {
+	ID        string  `xml:"ID,attr"`
+	Align     string  `xml:"ALIGN,attr,omitempty"`
+	Left      float64 `xml:"LEFT,attr,omitempty"`
+	Right     float64 `xml:"RIGHT,attr,omitempty"`
+	LineSpace float64 `xml:"LINESPACE,attr,omitempty"`
+	FirstLine float64 `xml:"FIRSTLINE,attr,omitempty"`
+}
+
+// Tags holds the tags that TAGREFS attributes refer to
+type Tags struct {
+	// Tags lists the tags in document order
+	Tags []Tag
+}
+
+// Tag is a LayoutTag, StructureTag, RoleTag, NamedEntityTag or OtherTag
+type Tag struct {
+	// Kind is the element name, e.g. "NamedEntityTag"
+	Kind string `xml:"-"`
+
+	ID          string `xml:"ID,attr"`
+	Type        string `xml:"TYPE,attr,omitempty"`
+	Label       string `xml:"LABEL,attr,omitempty"`
+	Description string `xml:"DESCRIPTION,attr,omitempty"`
+	URI         string `xml:"URI,attr,omitempty"`
+}
+
+// Layout holds the pages of the document
+type Layout struct {
+	Pages []Page `xml:"Page"`
+}
+
+// Page is a single page of the document
+type Page struct {
+	ID            string      `xml:"ID,attr"`
+	Width         float64     `xml:"WIDTH,attr"`
+	Height        float64     `xml:"HEIGHT,attr"`
+	PhysicalImgNr int         `xml:"PHYSICAL_IMG_NR,attr"`
+	PrintedImgNr  string      `xml:"PRINTED_IMG_NR,attr,omitempty"`
+	PrintSpace    *PrintSpace `xml:"PrintSpace,omitempty"`
+}
+
+// PrintSpace is the printed area of a page
+type PrintSpace struct {
+	HPos   float64
+	VPos   float64
+	Width  float64
+	Height float64
+
+	// Blocks holds *TextBlock, *Illustration, *GraphicalElement and
+	// *ComposedBlock values in document order
+	Blocks []Block
+}
+
+// Block is implemented by the block-level elements of a PrintSpace or
+// ComposedBlock
+type Block interface {
+	block()
+}
+
+// TextBlock is a block of text lines
+type TextBlock struct {
+	ID        string     `xml:"ID,attr"`
+	HPos      float64    `xml:"HPOS,attr"`
+	VPos      float64    `xml:"VPOS,attr"`
+	Width     float64    `xml:"WIDTH,attr"`
+	Height    float64    `xml:"HEIGHT,attr"`
+	Language  string     `xml:"LANG,attr,omitempty"`
+	StyleRefs string     `xml:"STYLEREFS,attr,omitempty"`
+	TagRefs   string     `xml:"TAGREFS,attr,omitempty"`
+	Lines     []TextLine `xml:"TextLine"`
+}
+
+// Illustration is a picture or figure region
+type Illustration struct {
+	ID     string  `xml:"ID,attr"`
+	HPos   float64 `xml:"HPOS,attr"`
+	VPos   float64 `xml:"VPOS,attr"`
+	Width  float64 `xml:"WIDTH,attr"`
+	Height float64 `xml:"HEIGHT,attr"`
+	Type   string  `xml:"TYPE,attr,omitempty"`
+}
+
+// GraphicalElement is a non-text region such as a separator line
+type GraphicalElement struct {
+	ID     string  `xml:"ID,attr"`
+	HPos   float64 `xml:"HPOS,attr"`
+	VPos   float64 `xml:"VPOS,attr"`
+	Width  float64 `xml:"WIDTH,attr"`
+	Height float64 `xml:"HEIGHT,attr"`
+}
+
+// ComposedBlock groups other blocks
+type ComposedBlock struct {
+	ID     string
+	HPos   float64
+	VPos   float64
+	Width  float64
+	Height float64
+	Type   string
+
+	// Blocks holds the nested blocks in document order
+	Blocks []Block
+}
+
+func (*TextBlock) block()        {}
+func (*Illustration) block()     {}
+func (*GraphicalElement) block() {}
+func (*ComposedBlock) block()    {}
+
+// TextLine is a single line of text
+type TextLine struct {
+	ID        string
+	HPos      float64
+	VPos      float64
+	Width     float64
+	Height    float64
+	Baseline  string
+	Language  string
+	StyleRefs string
+	TagRefs   string
+
+	// Items holds *String, *Space and *Hyphen values in document order
+	Items []Inline
+}
+
+// Inline is implemented by the elements of a TextLine
+type Inline interface {
+	inline()
+}
+
+// String is a recognised word
+type String struct {
+	ID        string   `xml:"ID,attr,omitempty"`
+	HPos      float64  `xml:"HPOS,attr"`
+	VPos      float64  `xml:"VPOS,attr"`
+	Width     float64  `xml:"WIDTH,attr"`
+	Height    float64  `xml:"HEIGHT,attr"`
+	Content   string   `xml:"CONTENT,attr"`
+	WC        *float64 `xml:"WC,attr,omitempty"` // nil if absent
+	CC        string   `xml:"CC,attr,omitempty"`
+	Language  string   `xml:"LANG,attr,omitempty"`
+	StyleRefs string   `xml:"STYLEREFS,attr,omitempty"`
+	TagRefs   string   `xml:"TAGREFS,attr,omitempty"`
+	SubsType  string   `xml:"SUBS_TYPE,attr,omitempty"`
+	SubsText  string   `xml:"SUBS_CONTENT,attr,omitempty"`
+	Glyphs    []Glyph  `xml:"Glyph"`
+}
+
+// Glyph is a single character of a String, which tesseract writes when
+// lstm_choice_mode is set
+type Glyph struct {
+	ID       string    `xml:"ID,attr,omitempty"`
+	HPos     float64   `xml:"HPOS,attr"`
+	VPos     float64   `xml:"VPOS,attr"`
+	Width    float64   `xml:"WIDTH,attr"`
+	Height   float64   `xml:"HEIGHT,attr"`
+	Content  string    `xml:"CONTENT,attr"`
+	GC       *float64  `xml:"GC,attr,omitempty"` // nil if absent
+	Variants []Variant `xml:"Variant"`
+}
+
+// Variant is an alternative reading of a Glyph
+type Variant struct {
+	Content string   `xml:"CONTENT,attr"`
+	VC      *float64 `xml:"VC,attr,omitempty"` // nil if absent
+}
+
+// Space is the white space between two words (SP)
+type Space struct {
+	ID    string  `xml:"ID,attr,omitempty"`
+	HPos  float64 `xml:"HPOS,attr"`
+	VPos  float64 `xml:"VPOS,attr"`
+	Width float64 `xml:"WIDTH,attr"`
+}
+
+// Hyphen marks a hyphenation at the end of a line (HYP)
+type Hyphen struct {
+	HPos    float64 `xml:"HPOS,attr"`
+	VPos    float64 `xml:"VPOS,attr"`
+	Width   float64 `xml:"WIDTH,attr"`
+	Content string  `xml:"CONTENT,attr"`
+}
+
+func (*String) inline() {}
+func (*Space) inline()  {}
+func (*Hyphen) inline() {}
+
+// Strings returns the words of the line
+func (l *TextLine) Strings() []*String {
+	var words []*String
+	for _, item := range l.Items {
+		if s, ok := item.(*String); ok {
+			words = append(words, s)
+		}
+	}
+	return words
+}
+
+// Decode reads an ALTO v3 or v4 document from r
+func Decode(r io.Reader) (*Document, error) {
+	d := xml.NewDecoder(r)
+	var start xml.StartElement
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("alto: %w", err)
+		}
+		if t, ok := tok.(xml.StartElement); ok {
+			start = t
+			break
+		}
+	}
+
+	var doc Document
+	if err := d.DecodeElement(&doc, &start); err != nil {
+		return nil, fmt.Errorf("alto: %w", err)
+	}
+	for _, a := range start.Attr {
+		if a.Name.Space == namespaceXSI && a.Name.Local == "schemaLocation" {
+			doc.SchemaLocation = a.Value
+		}
+	}
+	switch doc.XMLName.Space {
+	case NamespaceV3, NamespaceV4:
+		return &doc, nil
+	default:
+		return nil, fmt.Errorf("%w: namespace %q", ErrUnsupportedVersion, doc.XMLName.Space)
+	}
+}
+
+// Encode writes doc to w as an indented XML document. A document without
+// a namespace is written as ALTO v4.
+func Encode(w io.Writer, doc *Document) error {
+	start := xml.StartElement{Name: xml.Name{Space: doc.XMLName.Space, Local: "alto"}}
+	if start.Name.Space == "" {
+		start.Name.Space = NamespaceV4
+	}
+	if doc.SchemaLocation != "" {
+		start.Attr = []xml.Attr{
+			{Name: xml.Name{Local: "xmlns:xsi"}, Value: namespaceXSI},
+			{Name: xml.Name{Local: "xsi:schemaLocation"}, Value: doc.SchemaLocation},
+		}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.EncodeElement(doc, start); err != nil {
+		return fmt.Errorf("alto: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,215 @@
+package alto
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func decodeSample(t *testing.T) *Document {
+	t.Helper()
+	f, err := os.Open("testdata/sample.xml")
+	if err != nil {
+		t.Fatalf("Failed to open sample: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := Decode(f)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return doc
+}
+
+func TestDecode(t *testing.T) {
+	doc := decodeSample(t)
+
+	if doc.XMLName.Space != NamespaceV3 {
+		t.Errorf("namespace = %q, want %q", doc.XMLName.Space, NamespaceV3)
+	}
+	if got := doc.Description.OCRProcessing[0].OCRProcessingStep.ProcessingSoftware.SoftwareName; got != "tesseract 5.3.0" {
+		t.Errorf("software name = %q", got)
+	}
+
+	ps := doc.Layout.Pages[0].PrintSpace
+	if len(ps.Blocks) != 2 {
+		t.Fatalf("PrintSpace has %d blocks, want 2", len(ps.Blocks))
+	}
+	if _, ok := ps.Blocks[1].(*Illustration); !ok {
+		t.Errorf("second block is %T, want *Illustration", ps.Blocks[1])
+	}
+
+	block := ps.Blocks[0].(*TextBlock)
+	want := []Inline{
+		&String{ID: "string_0", HPos: 36, VPos: 20, Width: 144, Height: 40, WC: float(0.96), Content: "Fish&Chips"},
+		&Space{HPos: 180, VPos: 20, Width: 20},
+		&String{ID: "string_1", HPos: 200, VPos: 22, Width: 404, Height: 36, WC: float(0.91), Content: "£4.50"},
+	}
+	if !reflect.DeepEqual(block.Lines[0].Items, want) {
+		t.Errorf("first line items = %+v, want %+v", block.Lines[0].Items, want)
+	}
+	if _, ok := block.Lines[1].Items[1].(*Hyphen); !ok {
+		t.Errorf("second line item is %T, want *Hyphen", block.Lines[1].Items[1])
+	}
+	if got := len(block.Lines[1].Strings()); got != 1 {
+		t.Errorf("Strings() returned %d words, want 1", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := decodeSample(t)
+
+	// Post-edit a word before re-emitting the document
+	block := doc.Layout.Pages[0].PrintSpace.Blocks[0].(*TextBlock)
+	block.Lines[0].Strings()[1].Content = "£4.60"
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, doc); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `xmlns="`+NamespaceV3+`"`) {
+		t.Errorf("Encode() lost the ALTO namespace:\n%s", buf.String())
+	}
+
+	again, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() of encoded document error = %v", err)
+	}
+	if !reflect.DeepEqual(again, doc) {
+		t.Errorf("round trip changed the document:\ngot  %+v\nwant %+v", again, doc)
+	}
+}
+
+func TestRoundTripStylesAndTags(t *testing.T) {
+	f, err := os.Open("testdata/styles.xml")
+	if err != nil {
+		t.Fatalf("Failed to open sample: %v", err)
+	}
+	defer f.Close()
+	doc, err := Decode(f)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	wantStyles := &Styles{
+		TextStyles:      []TextStyle{{ID: "font_0", FontFamily: "Times New Roman", FontSize: 11.5, FontStyle: "bold"}},
+		ParagraphStyles: []ParagraphStyle{{ID: "par_0", Align: "Left", FirstLine: 12}},
+	}
+	if !reflect.DeepEqual(doc.Styles, wantStyles) {
+		t.Errorf("Styles = %+v, want %+v", doc.Styles, wantStyles)
+	}
+	wantTags := &Tags{Tags: []Tag{
+		{Kind: "NamedEntityTag", ID: "tag_0", Type: "person", Label: "Ada Lovelace"},
+		{Kind: "LayoutTag", ID: "tag_1", Label: "heading"},
+		{Kind: "OtherTag", ID: "tag_2", URI: "https://example.com/tags/2"},
+	}}
+	if !reflect.DeepEqual(doc.Tags, wantTags) {
+		t.Errorf("Tags = %+v, want %+v", doc.Tags, wantTags)
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, doc); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	again, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() of encoded document error = %v", err)
+	}
+	if !reflect.DeepEqual(again, doc) {
+		t.Errorf("round trip changed the document:\ngot  %+v\nwant %+v", again, doc)
+	}
+
+	block := again.Layout.Pages[0].PrintSpace.Blocks[0].(*TextBlock)
+	line := block.Lines[0]
+	word := line.Strings()[0]
+	for _, tt := range []struct {
+		level               string
+		styleRefs, tagRefs  string
+		wantStyle, wantTags string
+	}{
+		{"TextBlock", block.StyleRefs, block.TagRefs, "font_0 par_0", "tag_1"},
+		{"TextLine", line.StyleRefs, line.TagRefs, "font_0", "tag_1"},
+		{"String", word.StyleRefs, word.TagRefs, "font_0", "tag_0"},
+	} {
+		if tt.styleRefs != tt.wantStyle || tt.tagRefs != tt.wantTags {
+			t.Errorf("%s STYLEREFS, TAGREFS = %q, %q; want %q, %q", tt.level, tt.styleRefs, tt.tagRefs, tt.wantStyle, tt.wantTags)
+		}
+	}
+}
+
+// startAttrs lists the attributes of every element in data, in document
+// order, keyed by their prefixed names. Namespace declarations are left
+// out, as the encoder places them itself.
+func startAttrs(t *testing.T, data []byte) []map[string]string {
+	t.Helper()
+	var elems []map[string]string
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return elems
+		}
+		if err != nil {
+			t.Fatalf("RawToken() error = %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := map[string]string{"": start.Name.Local}
+		for _, a := range start.Attr {
+			name := a.Name.Local
+			if a.Name.Space != "" {
+				name = a.Name.Space + ":" + name
+			}
+			if a.Name.Space != "xmlns" && name != "xmlns" {
+				attrs[name] = a.Value
+			}
+		}
+		elems = append(elems, attrs)
+	}
+}
+
+func TestRoundTripAttributes(t *testing.T) {
+	data, err := os.ReadFile("testdata/glyphs.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	words := doc.Layout.Pages[0].PrintSpace.Blocks[0].(*TextBlock).Lines[0].Strings()
+	if got := len(words[0].Glyphs); got != 2 {
+		t.Errorf("first word has %d glyphs, want 2", got)
+	}
+	if words[1].WC == nil || *words[1].WC != 0 {
+		t.Errorf("second word WC = %v, want 0", words[1].WC)
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, doc); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, want := startAttrs(t, buf.Bytes()), startAttrs(t, data)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the attributes:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestDecodeUnsupportedVersion(t *testing.T) {
+	data := `<alto xmlns="http://www.loc.gov/standards/alto/ns-v2#"><Layout/></alto>`
+	if _, err := Decode(strings.NewReader(data)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Decode() error = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
@@ -0,0 +1,280 @@
+package alto
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// The elements below contain children of several types whose relative
+// order matters, which encoding/xml cannot express with struct tags alone.
+
+// boxAttrs holds the identifying and positional attributes shared by
+// the elements below
+type boxAttrs struct {
+	ID                        string
+	HPos, VPos, Width, Height float64
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (p *PrintSpace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs, err := parseAttrs(start)
+	if err != nil {
+		return err
+	}
+	p.HPos, p.VPos, p.Width, p.Height = attrs.HPos, attrs.VPos, attrs.Width, attrs.Height
+	p.Blocks, err = decodeBlocks(d)
+	return err
+}
+
+// MarshalXML implements xml.Marshaler
+func (p PrintSpace) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{
+		floatAttr("HPOS", p.HPos),
+		floatAttr("VPOS", p.VPos),
+		floatAttr("WIDTH", p.Width),
+		floatAttr("HEIGHT", p.Height),
+	}
+	return encodeBlocks(e, start, p.Blocks)
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (b *ComposedBlock) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs, err := parseAttrs(start)
+	if err != nil {
+		return err
+	}
+	b.ID, b.HPos, b.VPos, b.Width, b.Height = attrs.ID, attrs.HPos, attrs.VPos, attrs.Width, attrs.Height
+	b.Type = attrValue(start, "TYPE")
+	b.Blocks, err = decodeBlocks(d)
+	return err
+}
+
+// MarshalXML implements xml.Marshaler
+func (b ComposedBlock) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "ID"}, Value: b.ID},
+		floatAttr("HPOS", b.HPos),
+		floatAttr("VPOS", b.VPos),
+		floatAttr("WIDTH", b.Width),
+		floatAttr("HEIGHT", b.Height),
+	}
+	if b.Type != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "TYPE"}, Value: b.Type})
+	}
+	return encodeBlocks(e, start, b.Blocks)
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (t *Tags) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(d, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "LayoutTag", "StructureTag", "RoleTag", "NamedEntityTag", "OtherTag":
+		default:
+			return d.Skip()
+		}
+		tag := Tag{Kind: child.Name.Local}
+		if err := d.DecodeElement(&tag, &child); err != nil {
+			return err
+		}
+		t.Tags = append(t.Tags, tag)
+		return nil
+	})
+}
+
+// MarshalXML implements xml.Marshaler
+func (t Tags) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, tag := range t.Tags {
+		if tag.Kind == "" {
+			return fmt.Errorf("alto: tag %q has no kind", tag.ID)
+		}
+		if err := e.EncodeElement(tag, xml.StartElement{Name: xml.Name{Local: tag.Kind}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML implements xml.Unmarshaler
+func (l *TextLine) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs, err := parseAttrs(start)
+	if err != nil {
+		return err
+	}
+	l.ID, l.HPos, l.VPos, l.Width, l.Height = attrs.ID, attrs.HPos, attrs.VPos, attrs.Width, attrs.Height
+	l.Baseline = attrValue(start, "BASELINE")
+	l.Language = attrValue(start, "LANG")
+	l.StyleRefs = attrValue(start, "STYLEREFS")
+	l.TagRefs = attrValue(start, "TAGREFS")
+
+	return decodeChildren(d, func(child xml.StartElement) error {
+		var item Inline
+		switch child.Name.Local {
+		case "String":
+			item = &String{}
+		case "SP":
+			item = &Space{}
+		case "HYP":
+			item = &Hyphen{}
+		default:
+			return d.Skip()
+		}
+		if err := d.DecodeElement(item, &child); err != nil {
+			return err
+		}
+		l.Items = append(l.Items, item)
+		return nil
+	})
+}
+
+// MarshalXML implements xml.Marshaler
+func (l TextLine) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "ID"}, Value: l.ID},
+		floatAttr("HPOS", l.HPos),
+		floatAttr("VPOS", l.VPos),
+		floatAttr("WIDTH", l.Width),
+		floatAttr("HEIGHT", l.Height),
+	}
+	for _, a := range []struct{ name, value string }{
+		{"BASELINE", l.Baseline},
+		{"LANG", l.Language},
+		{"STYLEREFS", l.StyleRefs},
+		{"TAGREFS", l.TagRefs},
+	} {
+		if a.value != "" {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: a.name}, Value: a.value})
+		}
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, item := range l.Items {
+		var name string
+		switch item.(type) {
+		case *String:
+			name = "String"
+		case *Space:
+			name = "SP"
+		case *Hyphen:
+			name = "HYP"
+		default:
+			return fmt.Errorf("alto: unknown TextLine item %T", item)
+		}
+		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func decodeBlocks(d *xml.Decoder) ([]Block, error) {
+	var blocks []Block
+	err := decodeChildren(d, func(child xml.StartElement) error {
+		var b Block
+		switch child.Name.Local {
+		case "TextBlock":
+			b = &TextBlock{}
+		case "Illustration":
+			b = &Illustration{}
+		case "GraphicalElement":
+			b = &GraphicalElement{}
+		case "ComposedBlock":
+			b = &ComposedBlock{}
+		default:
+			return d.Skip()
+		}
+		if err := d.DecodeElement(b, &child); err != nil {
+			return err
+		}
+		blocks = append(blocks, b)
+		return nil
+	})
+	return blocks, err
+}
+
+func encodeBlocks(e *xml.Encoder, start xml.StartElement, blocks []Block) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		var name string
+		switch b.(type) {
+		case *TextBlock:
+			name = "TextBlock"
+		case *Illustration:
+			name = "Illustration"
+		case *GraphicalElement:
+			name = "GraphicalElement"
+		case *ComposedBlock:
+			name = "ComposedBlock"
+		default:
+			return fmt.Errorf("alto: unknown block %T", b)
+		}
+		if err := e.EncodeElement(b, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// decodeChildren calls fn for every child element until the enclosing
+// element ends. fn must consume the child, e.g. with DecodeElement or Skip.
+func decodeChildren(d *xml.Decoder, fn func(xml.StartElement) error) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// parseAttrs reads the ID, HPOS, VPOS, WIDTH and HEIGHT attributes of start
+func parseAttrs(start xml.StartElement) (boxAttrs, error) {
+	a := boxAttrs{ID: attrValue(start, "ID")}
+	for _, f := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"HPOS", &a.HPos},
+		{"VPOS", &a.VPos},
+		{"WIDTH", &a.Width},
+		{"HEIGHT", &a.Height},
+	} {
+		v := attrValue(start, f.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return a, fmt.Errorf("alto: %s %s=%q: %w", start.Name.Local, f.name, v, err)
+		}
+		*f.dst = n
+	}
+	return a, nil
+}
+
+func attrValue(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func floatAttr(name string, v float64) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: strconv.FormatFloat(v, 'f', -1, 64)}
+}
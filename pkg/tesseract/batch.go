@@ -0,0 +1,210 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// BatchItem is one input of a batch
+type BatchItem struct {
+	// Input is the image to recognise
+	Input Input
+
+	// Lang is passed to the client as for ImageToString
+	Lang string
+
+	// Formats are rendered with RecognizeInput; empty extracts text only,
+	// returned in Result.Text
+	Formats []Format
+
+	// Options adjust this item's run
+	Options []Options
+
+	// Context, if set, limits this item in addition to the batch context;
+	// its deadline passing fails the item with ErrProcessTimeout
+	Context context.Context
+}
+
+// BatchResult is the outcome of one BatchItem
+type BatchResult struct {
+	// Index is the position of the item in the batch
+	Index int
+
+	// Result holds the outputs when Err is nil
+	Result *Result
+
+	// Err is the item's error
+	Err error
+}
+
+// Batch runs OCR on many inputs with a bounded number of concurrent
+// tesseract processes. Each process is started with OMP_THREAD_LIMIT=1,
+// as running several multi-threaded tesseracts at once is slower than
+// running them single-threaded. A custom Config.Executor is responsible
+// for its own environment.
+type Batch struct {
+	client      *Client
+	concurrency int
+}
+
+// NewBatch creates a Batch running at most concurrency tesseract
+// processes at a time; zero or less means runtime.NumCPU()
+func NewBatch(c *Client, concurrency int) *Batch {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	worker := &Client{
+		config: c.config,
+		bin:    c.binary(),
+		env:    append(c.env[:len(c.env):len(c.env)], "OMP_THREAD_LIMIT=1"),
+	}
+	return &Batch{client: worker, concurrency: concurrency}
+}
+
+// Run processes items and returns their results in item order. If any item
+// fails, the error is a *BatchError listing the failures; the results of
+// the other items are still returned.
+func (b *Batch) Run(ctx context.Context, items []BatchItem) ([]BatchResult, error) {
+	results := make([]BatchResult, len(items))
+	for res := range b.Stream(ctx, items) {
+		results[res.Index] = res
+	}
+
+	var failed []*ItemError
+	for _, res := range results {
+		if res.Err != nil {
+			failed = append(failed, &ItemError{Index: res.Index, Err: res.Err})
+		}
+	}
+	if failed != nil {
+		return results, &BatchError{Total: len(items), Errors: failed}
+	}
+	return results, nil
+}
+
+// Stream processes items and sends each result as soon as it is ready, so
+// results arrive out of order. The channel is closed after the last
+// result and must be drained. Once ctx is done, the remaining items are
+// reported with ctx's error without being run.
+func (b *Batch) Stream(ctx context.Context, items []BatchItem) <-chan BatchResult {
+	out := make(chan BatchResult)
+
+	workers := b.concurrency
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	go func() {
+		defer close(out)
+
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					out <- b.run(ctx, i, items[i])
+				}
+			}()
+		}
+
+		for i := range items {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+	return out
+}
+
+// run processes a single item
+func (b *Batch) run(ctx context.Context, index int, item BatchItem) BatchResult {
+	res := BatchResult{Index: index}
+	if item.Context != nil {
+		if err := contextError(item.Context); err != nil {
+			res.Err = err
+			return res
+		}
+
+		// The item's deadline is copied so that it expires as a timeout
+		// rather than as a cancellation of ctx
+		var cancel context.CancelFunc
+		if deadline, ok := item.Context.Deadline(); ok {
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+		} else {
+			ctx, cancel = context.WithCancel(ctx)
+		}
+		defer cancel()
+		stop := context.AfterFunc(item.Context, func() {
+			if item.Context.Err() != context.DeadlineExceeded {
+				cancel()
+			}
+		})
+		defer stop()
+	}
+	if err := contextError(ctx); err != nil {
+		res.Err = err
+		return res
+	}
+
+	if len(item.Formats) == 0 {
+		var text string
+		text, res.Err = b.client.InputToStringContext(ctx, item.Input, item.Lang, item.Options...)
+		if res.Err == nil {
+			res.Result = &Result{Text: text}
+		}
+	} else {
+		res.Result, res.Err = b.client.RecognizeInputContext(ctx, item.Input, item.Lang, item.Formats, item.Options...)
+	}
+	return res
+}
+
+// ItemError is the error of one batch item
+type ItemError struct {
+	// Index is the position of the item in the batch
+	Index int
+
+	// Err is the item's error
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the item's error
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates the failed items of a batch. errors.Is and
+// errors.As match against every item's error.
+type BatchError struct {
+	// Total is the number of items in the batch
+	Total int
+
+	// Errors lists the failed items in item order
+	Errors []*ItemError
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d batch items failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the item errors
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
@@ -0,0 +1,209 @@
+package tesseract_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestBatch(t *testing.T) {
+	// Each run counts the runs in progress while it sleeps
+	var (
+		mu               sync.Mutex
+		running, maxSeen int
+	)
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.RespondFunc(func(tesseracttest.Call) tesseract.Execution {
+		mu.Lock()
+		running++
+		maxSeen = max(maxSeen, running)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return tesseract.Execution{Stdout: []byte("text\n")}
+	})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items := make([]tesseract.BatchItem, 12)
+	for i := range items {
+		items[i] = tesseract.BatchItem{Input: tesseract.ImageInput(testImage())}
+	}
+	items[3].Input = tesseract.Input{}
+	items[7].Context = cancelled
+
+	results, err := tesseract.NewBatch(client, 3).Run(context.Background(), items)
+
+	var batchErr *tesseract.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Total != 12 || len(batchErr.Errors) != 2 {
+		t.Fatalf("Run() error = %v, want *BatchError with 2 failures", err)
+	}
+	if !errors.Is(err, tesseract.ErrInvalidImage) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want it to match both item errors", err)
+	}
+	if batchErr.Errors[0].Index != 3 || batchErr.Errors[1].Index != 7 {
+		t.Errorf("failed items = %d, %d; want 3, 7", batchErr.Errors[0].Index, batchErr.Errors[1].Index)
+	}
+
+	for i, res := range results {
+		if res.Index != i {
+			t.Errorf("results[%d].Index = %d", i, res.Index)
+		}
+		if i == 3 || i == 7 {
+			continue
+		}
+		if res.Err != nil || res.Result.Text != "text\n" {
+			t.Errorf("results[%d] = %+v, %v", i, res.Result, res.Err)
+		}
+	}
+
+	if n := len(runs(exec)); n != 10 {
+		t.Errorf("tesseract ran %d times, want 10", n)
+	}
+	if maxSeen > 3 {
+		t.Errorf("%d tesseract processes ran at once, want at most 3", maxSeen)
+	}
+}
+
+func TestBatchThreadLimit(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "calls")
+	t.Setenv("TESSERACTTEST_LOG", log)
+	t.Setenv("TESSERACTTEST_LOG_ENV", "OMP_THREAD_LIMIT")
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+
+	items := []tesseract.BatchItem{
+		{Input: tesseract.ImageInput(testImage())},
+		{Input: tesseract.ImageInput(testImage())},
+	}
+	if _, err := tesseract.NewBatch(client, 2).Run(context.Background(), items); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var limits []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if fields := strings.Split(line, "\t"); len(fields) > 1 && fields[1] == "stdout" {
+			limits = append(limits, fields[len(fields)-1])
+		}
+	}
+	if want := []string{"OMP_THREAD_LIMIT=1", "OMP_THREAD_LIMIT=1"}; strings.Join(limits, " ") != strings.Join(want, " ") {
+		t.Errorf("recognition runs saw %q, want %q", limits, want)
+	}
+}
+
+// installerFunc adapts a function to tesseract.Installer
+type installerFunc func(ctx context.Context, langs ...string) error
+
+func (f installerFunc) Install(ctx context.Context, langs ...string) error { return f(ctx, langs...) }
+
+func TestBatchSharesInstalls(t *testing.T) {
+	// deu never shows up, so every run asks the installer for it
+	var (
+		mu               sync.Mutex
+		running, maxSeen int
+	)
+	installer := installerFunc(func(context.Context, ...string) error {
+		mu.Lock()
+		running++
+		maxSeen = max(maxSeen, running)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	client := newClient(t, tesseract.Config{Executor: exec, Installer: installer, Language: "deu"})
+
+	items := make([]tesseract.BatchItem, 4)
+	for i := range items {
+		items[i] = tesseract.BatchItem{Input: tesseract.ImageInput(testImage())}
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 4 {
+			client.ImageToString(testImage(), "deu")
+		}
+	}()
+	tesseract.NewBatch(client, 4).Run(context.Background(), items)
+	wg.Wait()
+
+	if maxSeen != 1 {
+		t.Errorf("%d installs ran at once, want 1", maxSeen)
+	}
+}
+
+func TestBatchItemTimeout(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+
+	running, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	items := []tesseract.BatchItem{
+		{Input: tesseract.ImageInput(testImage()), Context: running},
+		{Input: tesseract.ImageInput(testImage()), Context: expired},
+	}
+
+	start := time.Now()
+	results, _ := tesseract.NewBatch(client, 2).Run(context.Background(), items)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() returned after %v, want the item deadline to stop tesseract", elapsed)
+	}
+	for i, res := range results {
+		if !errors.Is(res.Err, tesseract.ErrProcessTimeout) {
+			t.Errorf("results[%d].Err = %v, want %v", i, res.Err, tesseract.ErrProcessTimeout)
+		}
+	}
+}
+
+func TestBatchStream(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	items := make([]tesseract.BatchItem, 5)
+	for i := range items {
+		items[i] = tesseract.BatchItem{Input: tesseract.ImageInput(testImage())}
+	}
+
+	var indices []int
+	for res := range tesseract.NewBatch(client, 2).Stream(context.Background(), items) {
+		if res.Err != nil {
+			t.Errorf("item %d: %v", res.Index, res.Err)
+		}
+		indices = append(indices, res.Index)
+	}
+	sort.Ints(indices)
+	if len(indices) != 5 || indices[0] != 0 || indices[4] != 4 {
+		t.Errorf("Stream() returned items %v, want 0-4", indices)
+	}
+
+	// A cancelled batch still reports every item
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := tesseract.NewBatch(client, 2).Run(ctx, items)
+	if !errors.Is(err, context.Canceled) || len(results) != 5 {
+		t.Errorf("Run() = %d results, %v; want 5 cancelled items", len(results), err)
+	}
+}
@@ -2,12 +2,10 @@ package tesseract
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"fmt"
 	"image"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -21,58 +19,44 @@ type Box struct {
 	Page   int
 }
 
-func (c *Client) ImageToBoxes(img image.Image, lang string) ([]Box, error) {
-	tmpDir, err := createTempDir()
-	if err != nil {
-		return nil, err
-	}
-	defer os.RemoveAll(tmpDir)
+func (c *Client) ImageToBoxes(img image.Image, lang string, opts ...Options) ([]Box, error) {
+	return c.ImageToBoxesContext(context.Background(), img, lang, opts...)
+}
 
-	imgPath, err := saveImage(tmpDir, img)
-	if err != nil {
+// ImageToBoxesContext is like ImageToBoxes but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToBoxesContext(ctx context.Context, img image.Image, lang string, opts ...Options) ([]Box, error) {
+	if err := validateImageFormat(img); err != nil {
 		return nil, err
 	}
 
-	outBase := filepath.Join(tmpDir, "output")
-	args := []string{
-		imgPath,
-		outBase,
-		"-c", "tessedit_create_boxfile=1",
-		"batch.nochop", "makebox",
-	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	if lang != "" {
-		if err := validateLanguage(lang); err != nil {
-			return nil, err
-		}
-		args = append(args, "-l", lang)
+	req, err := c.newRequest("", lang, opts)
+	if err != nil {
+		return nil, err
 	}
-	ctx := context.Background()
-	if c.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
-		defer cancel()
+	if err := req.require("tessedit_create_boxfile", "1"); err != nil {
+		return nil, err
 	}
-	// set context for the command
-	cmd := exec.CommandContext(ctx, TesseractCmd, args...)
+	req.configs = append(req.configs, "batch.nochop", "makebox")
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("tesseract error: %w", err)
+	res, err := c.runOCR(ctx, ImageInput(img), req)
+	if err != nil {
+		return nil, err
 	}
 
-	boxFile := outBase + ".box"
-	return parseBoxFile(boxFile)
-}
-
-func parseBoxFile(filename string) ([]Box, error) {
-	file, err := os.Open(filename)
+	boxFile, err := readOutput(res.Files, "box")
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	return parseBoxes(bytes.NewReader(boxFile))
+}
 
+func parseBoxes(r io.Reader) ([]Box, error) {
 	var boxes []Box
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		if len(fields) != 6 {
@@ -5,97 +5,216 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"os"
+	"sync"
 	"time"
 )
 
-// Client manages Tesseract OCR operations
+// Client manages Tesseract OCR operations. A Client is safe for concurrent
+// use; each one owns its tesseract binary and what it learned about it.
 type Client struct {
 	config Config
+
+	// env is added to the environment of every tesseract process
+	env []string
+
+	mu  sync.Mutex
+	bin *binary
+}
+
+// binary caches the version and languages of one tesseract binary
+type binary struct {
+	path string
+
+	// versionMu guards version, which is only set once tesseract --version
+	// succeeded, so a failed check is retried on the next run
+	versionMu sync.Mutex
+	version   *VersionInfo
+
+	langsMu sync.Mutex
+	langs   []string
+
+	// tessdata is the directory --list-langs reported, empty if tesseract
+	// did not name it, and tessdataStamp its modification time when the
+	// languages were listed
+	tessdata      string
+	tessdataStamp time.Time
+
+	// installMu serialises Config.Installer runs of every client sharing
+	// the binary, like a Batch and the client it was made from
+	installMu sync.Mutex
 }
 
 // NewClient creates a new Tesseract client with the given configuration
 func NewClient(cfg Config) (*Client, error) {
-	if cfg.TesseractPath != "" {
-		if err := SetTesseractCmd(cfg.TesseractPath); err != nil {
+	c := &Client{config: cfg}
+	if cfg.Executor == nil {
+		cmd := cfg.TesseractPath
+		if cmd == "" {
+			cmd = defaultTesseractCmd
+		}
+		path, err := resolveTesseractCmd(cmd)
+		if err != nil {
 			return nil, err
 		}
+		c.bin = &binary{path: path}
 	}
-	if err := checkVersion(); err != nil {
+	if _, err := c.tesseractVersion(); err != nil {
 		return nil, err
 	}
-	return &Client{config: cfg}, nil
+	return c, nil
 }
 
-// ImageToString performs OCR on an image using the default client
-func ImageToString(img image.Image, lang string) (string, error) {
-	return DefaultClient.ImageToString(img, lang)
+// binary returns the client's tesseract binary. Clients built without
+// NewClient, like DefaultClient, use Config.TesseractPath or look
+// tesseract up in PATH on every run.
+func (c *Client) binary() *binary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bin == nil {
+		path := c.config.TesseractPath
+		if path == "" {
+			path = defaultTesseractCmd
+		}
+		c.bin = &binary{path: path}
+	}
+	return c.bin
 }
 
-// ImageToString performs OCR on an image and returns the extracted text
-func (c *Client) ImageToString(img image.Image, lang string) (string, error) {
-	if err := validateImageFormat(img); err != nil {
-		return "", err
+// setBinary switches the client to the binary at path, dropping the
+// cached version and languages
+func (c *Client) setBinary(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bin = &binary{path: path}
+}
+
+// tesseractVersion returns the installed tesseract version, detecting it
+// on first use and again after a failed detection
+func (c *Client) tesseractVersion() (VersionInfo, error) {
+	b := c.binary()
+	b.versionMu.Lock()
+	defer b.versionMu.Unlock()
+	if b.version != nil {
+		return *b.version, nil
+	}
+	info, err := c.checkVersion(context.Background(), b)
+	if err != nil {
+		return VersionInfo{}, err
 	}
+	b.version = &info
+	return info, nil
+}
 
-	ctx := context.Background()
+// withTimeout derives a context bounded by Config.Timeout, if one is set
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	if c.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
-		defer cancel()
+		return context.WithTimeout(ctx, c.config.Timeout)
 	}
+	return context.WithCancel(ctx)
+}
 
-	out, err := runOCR(ctx, img, lang, []string{"stdout"})
-	if err != nil {
-		return "", err
-	}
-	return string(out), nil
+// ImageToString performs OCR on an image using the default client
+func ImageToString(img image.Image, lang string, opts ...Options) (string, error) {
+	return DefaultClient.ImageToString(img, lang, opts...)
 }
 
-// ImageToOutput performs OCR and returns the result in the specified format
-func (c *Client) ImageToOutput(img image.Image, lang string, outputType OutputType) (interface{}, error) {
+// ImageToStringContext performs OCR on an image using the default client
+// and the given context
+func ImageToStringContext(ctx context.Context, img image.Image, lang string, opts ...Options) (string, error) {
+	return DefaultClient.ImageToStringContext(ctx, img, lang, opts...)
+}
+
+// ImageToString performs OCR on an image and returns the extracted text
+func (c *Client) ImageToString(img image.Image, lang string, opts ...Options) (string, error) {
+	return c.ImageToStringContext(context.Background(), img, lang, opts...)
+}
+
+// ImageToStringContext is like ImageToString but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToStringContext(ctx context.Context, img image.Image, lang string, opts ...Options) (string, error) {
+	return c.InputToStringContext(ctx, ImageInput(img), lang, opts...)
+}
+
+// ImageToOutput performs OCR and returns the result in the specified format.
+// Pass OutputDefault to use Config.OutputType.
+func (c *Client) ImageToOutput(img image.Image, lang string, outputType OutputType, opts ...Options) (interface{}, error) {
+	return c.ImageToOutputContext(context.Background(), img, lang, outputType, opts...)
+}
+
+// ImageToOutputContext is like ImageToOutput but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToOutputContext(ctx context.Context, img image.Image, lang string, outputType OutputType, opts ...Options) (interface{}, error) {
 	if err := validateImageFormat(img); err != nil {
 		return "", err
 	}
 
-	ctx := context.Background()
-	if c.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
-		defer cancel()
+	if outputType == OutputDefault {
+		outputType = c.config.OutputType
+	}
+	if outputType == OutputDict {
+		out, err := c.extension(ctx, ImageInput(img), lang, "tsv", opts, nil)
+		if err != nil {
+			return nil, err
+		}
+		return parseTSV(string(out)), nil
+	}
+
+	req, err := c.newRequest("stdout", lang, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	out, err := runOCR(ctx, img, lang, []string{"stdout"})
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.runOCR(ctx, ImageInput(img), req)
 	if err != nil {
 		return nil, err
 	}
 
 	switch outputType {
 	case OutputString:
-		return string(out), nil
+		return string(res.Stdout), nil
 	case OutputBytes:
-		return out, nil
-	case OutputDict:
-		return parseTSV(string(out)), nil
+		return res.Stdout, nil
 	default:
 		return nil, fmt.Errorf("unsupported output type")
 	}
 }
 
 // ImageToFile performs OCR and saves the result to the specified file
-func (c *Client) ImageToFile(img image.Image, lang, outputFile string) error {
+func (c *Client) ImageToFile(img image.Image, lang, outputFile string, opts ...Options) error {
+	return c.ImageToFileContext(context.Background(), img, lang, outputFile, opts...)
+}
+
+// ImageToFileContext is like ImageToFile but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToFileContext(ctx context.Context, img image.Image, lang, outputFile string, opts ...Options) error {
 	if err := validateImageFormat(img); err != nil {
 		return err
 	}
 
-	ctx := context.Background()
-	if c.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
-		defer cancel()
+	req, err := c.newRequest("", lang, opts)
+	if err != nil {
+		return err
 	}
 
-	_, err := runOCR(ctx, img, lang, []string{outputFile})
-	return err
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.runOCR(ctx, ImageInput(img), req)
+	if err != nil {
+		return err
+	}
+
+	// Tesseract appends a suffix per renderer to the output base
+	for suffix, data := range res.Files {
+		if err := os.WriteFile(outputFile+"."+suffix, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DefaultClient provides a pre-configured client instance
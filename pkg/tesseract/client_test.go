@@ -0,0 +1,465 @@
+package tesseract_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestImageToStringContextCancel(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.ImageToStringContext(ctx, testImage(), "")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ImageToStringContext() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ImageToStringContext() returned after %v, want prompt return", elapsed)
+	}
+}
+
+func TestImageToStringContextTimeout(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+
+	tests := []struct {
+		name   string
+		config tesseract.Config
+		ctx    func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name:   "Context deadline",
+			config: tesseract.Config{},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+		},
+		{
+			name:   "Config timeout",
+			config: tesseract.Config{Timeout: 100 * time.Millisecond},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.TesseractPath = fakeBinary
+			client := newClient(t, tt.config)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := client.ImageToStringContext(ctx, testImage(), "")
+			if !tesseract.IsTimeout(err) {
+				t.Errorf("ImageToStringContext() error = %v, want timeout", err)
+			}
+		})
+	}
+}
+
+func TestConfigCommandLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		config tesseract.Config
+		lang   string
+		want   []string
+	}{
+		{
+			name:   "Empty config",
+			config: tesseract.Config{},
+			want:   []string{"stdout"},
+		},
+		{
+			name:   "Default language",
+			config: tesseract.Config{Language: "deu"},
+			want:   []string{"stdout", "-l", "deu"},
+		},
+		{
+			name:   "Per-call language wins",
+			config: tesseract.Config{Language: "deu"},
+			lang:   "eng",
+			want:   []string{"stdout", "-l", "eng"},
+		},
+		{
+			name:   "Config file",
+			config: tesseract.Config{Language: "eng", ConfigFile: "/etc/tess.cfg"},
+			want:   []string{"stdout", "-l", "eng", "/etc/tess.cfg"},
+		},
+		{
+			name:   "Tessdata directory",
+			config: tesseract.Config{Language: "eng", TessdataDir: "/opt/tessdata"},
+			want:   []string{"stdout", "-l", "eng", "--tessdata-dir", "/opt/tessdata"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor("5.3.0", "eng", "deu")
+			exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+			tt.config.Executor = exec
+			client := newClient(t, tt.config)
+
+			if _, err := client.ImageToString(testImage(), tt.lang); err != nil {
+				t.Fatalf("ImageToString() error = %v", err)
+			}
+			if got := runArgs(exec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("command line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigNice(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Config.Nice is only honoured on Linux")
+	}
+	log := filepath.Join(t.TempDir(), "calls")
+	t.Setenv("TESSERACTTEST_LOG", log)
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary, Nice: 5})
+
+	var info tesseract.RunInfo
+	text, err := client.ImageToString(testImage(), "eng", tesseract.Options{Info: &info})
+	if err != nil {
+		t.Fatalf("ImageToString() error = %v", err)
+	}
+	if want := string(fixture(t, "output.txt")); text != want {
+		t.Errorf("ImageToString() = %q, want %q", text, want)
+	}
+
+	// tesseract is started through nice(1)
+	want := []string{"nice", "-n", "5", fakeBinary}
+	if len(info.CommandLine) < len(want) || !reflect.DeepEqual(info.CommandLine[:len(want)], want) {
+		t.Errorf("CommandLine = %q, want it to start with %q", info.CommandLine, want)
+	}
+
+	// nice(1) hands tesseract its arguments unchanged
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if got := strings.Split(lines[len(lines)-1], "\t")[1:]; !reflect.DeepEqual(got, []string{"stdout", "-l", "eng"}) {
+		t.Errorf("command line = %q, want %q", got, []string{"stdout", "-l", "eng"})
+	}
+}
+
+func TestConfigNiceNotInstalled(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Config.Nice is only honoured on Linux")
+	}
+	t.Setenv("PATH", t.TempDir())
+
+	// The version probe already runs tesseract under nice
+	_, err := tesseract.NewClient(tesseract.Config{TesseractPath: fakeBinary, Nice: 5})
+	if !errors.Is(err, tesseract.ErrInvalidConfig) || tesseract.IsNotFound(err) {
+		t.Errorf("NewClient() without nice error = %v, want %v", err, tesseract.ErrInvalidConfig)
+	}
+}
+
+func TestConfigOutputType(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+	client := newClient(t, tesseract.Config{Executor: exec, OutputType: tesseract.OutputBytes})
+
+	tests := []struct {
+		name       string
+		outputType tesseract.OutputType
+		want       interface{}
+	}{
+		{"Default", tesseract.OutputDefault, []byte("text\n")},
+		{"Explicit", tesseract.OutputString, "text\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.ImageToOutput(testImage(), "", tt.outputType)
+			if err != nil {
+				t.Fatalf("ImageToOutput() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImageToOutput() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSegAndEngineMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		config  tesseract.Config
+		opts    []tesseract.Options
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "Config page segmentation",
+			config: tesseract.Config{PageSegMode: tesseract.PSMSingleBlock},
+			want:   []string{"stdout", "--psm", "6"},
+		},
+		{
+			name:   "Per-call override",
+			config: tesseract.Config{PageSegMode: tesseract.PSMSingleBlock, EngineMode: tesseract.OEMLSTMOnly},
+			opts:   []tesseract.Options{{PageSegMode: tesseract.PSMSparseText}},
+			want:   []string{"stdout", "--psm", "11", "--oem", "1"},
+		},
+		{
+			name: "OSD only",
+			opts: []tesseract.Options{{PageSegMode: tesseract.PSMOSDOnly}},
+			want: []string{"stdout", "--psm", "0"},
+		},
+		{
+			name:    "Invalid page segmentation",
+			opts:    []tesseract.Options{{PageSegMode: tesseract.PageSegMode(42)}},
+			wantErr: tesseract.ErrInvalidConfig,
+		},
+		{
+			name:    "Engine mode on tesseract 3",
+			version: "3.05.02",
+			opts:    []tesseract.Options{{EngineMode: tesseract.OEMLSTMOnly}},
+			wantErr: tesseract.ErrFeatureUnsupported,
+		},
+		{
+			name:    "Legacy engine mode on tesseract 3",
+			version: "3.05.02",
+			opts:    []tesseract.Options{{EngineMode: tesseract.OEMTesseractOnly}},
+			want:    []string{"stdout", "--oem", "0"},
+		},
+		{
+			name:    "Raw line on tesseract 3",
+			version: "3.05.02",
+			opts:    []tesseract.Options{{PageSegMode: tesseract.PSMRawLine}},
+			wantErr: tesseract.ErrFeatureUnsupported,
+		},
+		{
+			name:    "Page segmentation on tesseract 3",
+			version: "3.05.02",
+			opts:    []tesseract.Options{{PageSegMode: tesseract.PSMSingleLine}},
+			want:    []string{"stdout", "--psm", "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := tt.version
+			if version == "" {
+				version = "5.3.0"
+			}
+			exec := tesseracttest.NewExecutor(version)
+			exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+			tt.config.Executor = exec
+			client := newClient(t, tt.config)
+
+			_, err := client.ImageToString(testImage(), "", tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImageToString() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := runArgs(exec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("command line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  tesseract.Config
+		opts    []tesseract.Options
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "Config variables",
+			config: tesseract.Config{Variables: map[string]string{
+				"tessedit_char_whitelist":   "0123456789",
+				"preserve_interword_spaces": "1",
+			}},
+			want: []string{
+				"stdout",
+				"-c", "preserve_interword_spaces=1",
+				"-c", "tessedit_char_whitelist=0123456789",
+			},
+		},
+		{
+			name:   "Per-call variables merged",
+			config: tesseract.Config{Variables: map[string]string{"load_system_dawg": "0", "load_freq_dawg": "0"}},
+			opts:   []tesseract.Options{{Variables: map[string]string{"load_system_dawg": "1"}}},
+			want: []string{
+				"stdout",
+				"-c", "load_freq_dawg=0",
+				"-c", "load_system_dawg=1",
+			},
+		},
+		{
+			name:    "Invalid name",
+			opts:    []tesseract.Options{{Variables: map[string]string{"bad name": "1"}}},
+			wantErr: tesseract.ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor("5.3.0")
+			exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+			tt.config.Executor = exec
+			client := newClient(t, tt.config)
+
+			_, err := client.ImageToString(testImage(), "", tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImageToString() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := runArgs(exec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("command line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageToExtensionVariableConflict(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"hocr": []byte("<html/>\n")}})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantErr error
+	}{
+		{"No override", nil, nil},
+		{"Matching override", map[string]string{"tessedit_create_hocr": "1"}, nil},
+		{"Conflicting override", map[string]string{"tessedit_create_hocr": "0"}, tesseract.ErrVariableConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ImageToExtension(testImage(), "", "hocr", tesseract.Options{Variables: tt.vars})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ImageToExtension() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageToHOCR(t *testing.T) {
+	sample, err := os.ReadFile(filepath.Join("hocr", "testdata", "sample.hocr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"hocr": sample}})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	doc, err := client.ImageToHOCR(testImage(), "")
+	if err != nil {
+		t.Fatalf("ImageToHOCR() error = %v", err)
+	}
+	if len(doc.Pages) != 1 || len(doc.Pages[0].Areas) != 2 {
+		t.Fatalf("ImageToHOCR() = %+v, want one page with two areas", doc)
+	}
+	if got := doc.Pages[0].Areas[0].Paragraphs[0].Lines[0].Text(); got != "Fish&Chips £4.50" {
+		t.Errorf("first line = %q", got)
+	}
+}
+
+func TestImageToALTO(t *testing.T) {
+	sample, err := os.ReadFile(filepath.Join("alto", "testdata", "sample.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"XML suffix", "xml"},
+		{"ALTO suffix", "alto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor("5.3.0")
+			exec.Respond(tesseract.Execution{Files: map[string][]byte{tt.file: sample}})
+			client := newClient(t, tesseract.Config{Executor: exec})
+
+			doc, err := client.ImageToALTO(testImage(), "")
+			if err != nil {
+				t.Fatalf("ImageToALTO() error = %v", err)
+			}
+			if got := len(doc.Layout.Pages); got != 1 {
+				t.Errorf("ImageToALTO() returned %d pages, want 1", got)
+			}
+		})
+	}
+}
+
+// buildFake builds a fake tesseract whose recognised text is text
+func buildFake(t *testing.T, text string) string {
+	t.Helper()
+	path, err := tesseracttest.Build(t.TempDir(), fixtureDir(t, map[string]string{"output.txt": text}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClientsWithDifferentBinaries(t *testing.T) {
+	paths := []string{buildFake(t, "first\n"), buildFake(t, "second\n")}
+	want := []string{"first\n", "second\n"}
+	t.Cleanup(func() {
+		if err := tesseract.SetTesseractCmd("tesseract"); err != nil {
+			t.Errorf("SetTesseractCmd() error = %v", err)
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		n := i % 2
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client, err := tesseract.NewClient(tesseract.Config{TesseractPath: paths[n]})
+			if err != nil {
+				t.Errorf("NewClient() error = %v", err)
+				return
+			}
+			text, err := client.ImageToString(testImage(), "")
+			if err != nil || text != want[n] {
+				t.Errorf("ImageToString() = %q, %v; want %q", text, err, want[n])
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := tesseract.SetTesseractCmd(paths[n]); err != nil {
+				t.Errorf("SetTesseractCmd() error = %v", err)
+				return
+			}
+			text, err := tesseract.ImageToString(testImage(), "")
+			if err != nil || (text != want[0] && text != want[1]) {
+				t.Errorf("ImageToString() = %q, %v", text, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -2,17 +2,17 @@
 package tesseract
 
 import (
-	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"time"
 )
 
-var (
-	// TesseractCmd is the command used to execute Tesseract OCR
-	TesseractCmd = "tesseract"
+// defaultTesseractCmd is the command run when no TesseractPath is set
+const defaultTesseractCmd = "tesseract"
 
+var (
 	// defaultEncoding defines the character encoding for OCR output
 	defaultEncoding = "utf-8"
 
@@ -30,19 +30,51 @@ type Config struct {
 	// TesseractPath specifies custom path to tesseract executable
 	TesseractPath string
 
-	// Language specifies OCR language(s) (e.g., "eng", "eng+fra")
+	// Language specifies OCR language(s) (e.g., "eng", "eng+fra"), used
+	// when a call passes an empty lang
 	Language string
 
-	// ConfigFile path to custom Tesseract configuration file
+	// ConfigFile path to custom Tesseract configuration file, passed to
+	// every recognition run ahead of any built-in configs
 	ConfigFile string
 
+	// TessdataDir is the directory holding the traineddata files, passed
+	// as --tessdata-dir to every run. Empty leaves the choice to tesseract
+	// (TESSDATA_PREFIX or its built-in path).
+	TessdataDir string
+
+	// Installer, if set, installs languages a run needs that tesseract
+	// does not list, before the run starts. Point it at TessdataDir; see
+	// the tessdata package.
+	Installer Installer
+
 	// Timeout sets maximum duration for OCR operations
 	Timeout time.Duration
 
-	// Nice sets process priority (Unix-like systems only)
+	// Nice sets process priority via nice(1) (Linux only, ignored elsewhere)
 	Nice int
 
-	// OutputType specifies the format of OCR output
+	// PageSegMode sets the default page segmentation mode (--psm)
+	PageSegMode PageSegMode
+
+	// EngineMode sets the default OCR engine mode (--oem); any mode other
+	// than OEMDefault requires Tesseract 4.0 or newer
+	EngineMode EngineMode
+
+	// Variables are Tesseract parameters passed as "-c name=value" on
+	// every run, e.g. tessedit_char_whitelist or preserve_interword_spaces
+	Variables map[string]string
+
+	// UseStdin streams images to tesseract on stdin ("tesseract stdin ...")
+	// instead of writing a temporary input file. A temporary directory is
+	// still used for outputs tesseract writes next to an output base.
+	UseStdin bool
+
+	// Executor runs the tesseract binary; nil uses a CommandExecutor
+	Executor Executor
+
+	// OutputType specifies the format of OCR output used by ImageToOutput
+	// when it is called with OutputDefault
 	OutputType OutputType
 }
 
@@ -58,20 +90,34 @@ const (
 
 	// OutputDict returns OCR result as key-value pairs
 	OutputDict
+
+	// OutputDefault selects the client's Config.OutputType
+	OutputDefault OutputType = -1
 )
 
-// SetTesseractCmd sets the path to the Tesseract executable
-// It validates the path exists and is executable
+// SetTesseractCmd sets the path to the Tesseract executable used by
+// DefaultClient and the package-level functions. It validates the path
+// exists and is executable. Clients created with NewClient are unaffected;
+// use Config.TesseractPath for those.
 func SetTesseractCmd(cmd string) error {
+	path, err := resolveTesseractCmd(cmd)
+	if err != nil {
+		return err
+	}
+	DefaultClient.setBinary(path)
+	return nil
+}
+
+// resolveTesseractCmd returns the path of cmd, looking it up in PATH when
+// it is not a file
+func resolveTesseractCmd(cmd string) (string, error) {
 	if _, err := os.Stat(cmd); err != nil {
 		if !filepath.IsAbs(cmd) {
 			if path, err := exec.LookPath(cmd); err == nil {
-				TesseractCmd = path
-				return nil
+				return path, nil
 			}
 		}
-		return errors.New("tesseract binary not found")
+		return "", fmt.Errorf("%w: %s", ErrTesseractNotFound, cmd)
 	}
-	TesseractCmd = cmd
-	return nil
+	return cmd, nil
 }
@@ -0,0 +1,146 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"image"
+	"strings"
+)
+
+// Level identifies the layout element a TSV record describes
+type Level int
+
+const (
+	// LevelPage marks a page record
+	LevelPage Level = iota + 1
+
+	// LevelBlock marks a text block record
+	LevelBlock
+
+	// LevelParagraph marks a paragraph record
+	LevelParagraph
+
+	// LevelLine marks a text line record
+	LevelLine
+
+	// LevelWord marks a word record
+	LevelWord
+)
+
+// Word is a single record of Tesseract's TSV output. Despite the name it
+// may describe any Level; only LevelWord records carry Text and Conf.
+type Word struct {
+	Level    Level
+	PageNum  int
+	BlockNum int
+	ParNum   int
+	LineNum  int
+	WordNum  int
+
+	// Bounds is the bounding box in image pixel coordinates
+	Bounds image.Rectangle
+
+	// Conf is the recognition confidence in [0, 100], or -1 for records
+	// that are not words
+	Conf float64
+
+	Text string
+}
+
+// WordGroup is a block, paragraph or line together with its words
+type WordGroup struct {
+	Level    Level
+	PageNum  int
+	BlockNum int
+	ParNum   int
+	LineNum  int
+
+	// Bounds is the smallest rectangle containing all Words
+	Bounds image.Rectangle
+
+	Words []Word
+}
+
+// Text joins the group's words with spaces, starting a new line whenever
+// the words move to a different line
+func (g WordGroup) Text() string {
+	var sb strings.Builder
+	for i, w := range g.Words {
+		if i > 0 {
+			if w.lineKey() != g.Words[i-1].lineKey() {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(w.Text)
+	}
+	return sb.String()
+}
+
+// lineKey identifies the line a record belongs to
+func (w Word) lineKey() [4]int {
+	return [4]int{w.PageNum, w.BlockNum, w.ParNum, w.LineNum}
+}
+
+// groupKey identifies the element at level a record belongs to
+func (w Word) groupKey(level Level) [4]int {
+	key := w.lineKey()
+	switch level {
+	case LevelBlock:
+		key[2], key[3] = 0, 0
+	case LevelParagraph:
+		key[3] = 0
+	}
+	return key
+}
+
+// Blocks groups the word records in words by text block
+func Blocks(words []Word) []WordGroup {
+	return groupWords(words, LevelBlock)
+}
+
+// Paragraphs groups the word records in words by paragraph
+func Paragraphs(words []Word) []WordGroup {
+	return groupWords(words, LevelParagraph)
+}
+
+// Lines groups the word records in words by text line
+func Lines(words []Word) []WordGroup {
+	return groupWords(words, LevelLine)
+}
+
+func groupWords(words []Word, level Level) []WordGroup {
+	var groups []WordGroup
+	for _, w := range words {
+		if w.Level != LevelWord {
+			continue
+		}
+		key := w.groupKey(level)
+		if n := len(groups); n == 0 || groups[n-1].Words[0].groupKey(level) != key {
+			groups = append(groups, WordGroup{
+				Level:    level,
+				PageNum:  key[0],
+				BlockNum: key[1],
+				ParNum:   key[2],
+				LineNum:  key[3],
+				Bounds:   w.Bounds,
+			})
+		}
+		g := &groups[len(groups)-1]
+		g.Bounds = g.Bounds.Union(w.Bounds)
+		g.Words = append(g.Words, w)
+	}
+	return groups
+}
+
+// ImageToData performs OCR and returns Tesseract's TSV output as records
+func (c *Client) ImageToData(img image.Image, lang string, opts ...Options) ([]Word, error) {
+	return c.ImageToDataContext(context.Background(), img, lang, opts...)
+}
+
+// ImageToDataContext is like ImageToData but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToDataContext(ctx context.Context, img image.Image, lang string, opts ...Options) ([]Word, error) {
+	return c.InputToDataContext(ctx, ImageInput(img), lang, opts...)
+}
@@ -0,0 +1,114 @@
+package tesseract
+
+import (
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+)
+
+const sampleTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t400\t200\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t10\t10\t300\t80\t-1\t\n" +
+	"3\t1\t1\t1\t0\t0\t10\t10\t300\t80\t-1\t\n" +
+	"4\t1\t1\t1\t1\t0\t10\t10\t200\t30\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t10\t10\t90\t30\t96.5\tHello\n" +
+	"5\t1\t1\t1\t1\t2\t110\t12\t100\t28\t91.25\tworld\n" +
+	"4\t1\t1\t1\t2\t0\t10\t50\t300\t40\t-1\t\n" +
+	"5\t1\t1\t1\t2\t1\t10\t50\t300\t40\t88\tagain\n" +
+	"2\t1\t2\t0\t0\t0\t10\t120\t100\t30\t-1\t\n" +
+	"3\t1\t2\t1\t0\t0\t10\t120\t100\t30\t-1\t\n" +
+	"4\t1\t2\t1\t1\t0\t10\t120\t100\t30\t-1\t\n" +
+	"5\t1\t2\t1\t1\t1\t10\t120\t100\t30\t75\tTotal\n"
+
+func TestParseWords(t *testing.T) {
+	words, err := parseWords(sampleTSV)
+	if err != nil {
+		t.Fatalf("parseWords() error = %v", err)
+	}
+	if len(words) != 12 {
+		t.Fatalf("parseWords() returned %d records, want 12", len(words))
+	}
+
+	want := Word{
+		Level: LevelWord, PageNum: 1, BlockNum: 1, ParNum: 1, LineNum: 1, WordNum: 2,
+		Bounds: image.Rect(110, 12, 210, 40),
+		Conf:   91.25,
+		Text:   "world",
+	}
+	if !reflect.DeepEqual(words[5], want) {
+		t.Errorf("parseWords()[5] = %+v, want %+v", words[5], want)
+	}
+	if words[0].Level != LevelPage || words[0].Conf != -1 || words[0].Text != "" {
+		t.Errorf("parseWords()[0] = %+v, want empty page record", words[0])
+	}
+}
+
+func TestParseWordsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"Plain text", "Hello world\n"},
+		{"Bad number", "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\nx\t1\t1\t1\t1\t1\t0\t0\t1\t1\t90\ta\n"},
+		{"Short row", "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n5\t1\t1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseWords(tt.data); !errors.Is(err, ErrInvalidOutput) {
+				t.Errorf("parseWords() error = %v, want %v", err, ErrInvalidOutput)
+			}
+		})
+	}
+}
+
+func TestGroupWords(t *testing.T) {
+	words, err := parseWords(sampleTSV)
+	if err != nil {
+		t.Fatalf("parseWords() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		groups     []WordGroup
+		wantText   []string
+		wantBounds []image.Rectangle
+	}{
+		{
+			name:       "Lines",
+			groups:     Lines(words),
+			wantText:   []string{"Hello world", "again", "Total"},
+			wantBounds: []image.Rectangle{image.Rect(10, 10, 210, 40), image.Rect(10, 50, 310, 90), image.Rect(10, 120, 110, 150)},
+		},
+		{
+			name:       "Paragraphs",
+			groups:     Paragraphs(words),
+			wantText:   []string{"Hello world\nagain", "Total"},
+			wantBounds: []image.Rectangle{image.Rect(10, 10, 310, 90), image.Rect(10, 120, 110, 150)},
+		},
+		{
+			name:       "Blocks",
+			groups:     Blocks(words),
+			wantText:   []string{"Hello world\nagain", "Total"},
+			wantBounds: []image.Rectangle{image.Rect(10, 10, 310, 90), image.Rect(10, 120, 110, 150)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var text []string
+			var bounds []image.Rectangle
+			for _, g := range tt.groups {
+				text = append(text, g.Text())
+				bounds = append(bounds, g.Bounds)
+			}
+			if !reflect.DeepEqual(text, tt.wantText) {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+			if !reflect.DeepEqual(bounds, tt.wantBounds) {
+				t.Errorf("bounds = %v, want %v", bounds, tt.wantBounds)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package tesseract_test
+
+import (
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestImageToData(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"tsv": fixture(t, "output.tsv")}}, "tessedit_create_tsv=1")
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	words, err := client.ImageToData(testImage(), "")
+	if err != nil {
+		t.Fatalf("ImageToData() error = %v", err)
+	}
+	if len(words) != 12 {
+		t.Errorf("ImageToData() returned %d records, want 12", len(words))
+	}
+
+	dict, err := client.ImageToOutput(testImage(), "", tesseract.OutputDict)
+	if err != nil {
+		t.Fatalf("ImageToOutput() error = %v", err)
+	}
+	if got := dict.(map[string][]string)["text"][4]; got != "Hello" {
+		t.Errorf("ImageToOutput() text[4] = %q, want %q", got, "Hello")
+	}
+}
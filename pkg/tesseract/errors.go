@@ -2,6 +2,7 @@
 package tesseract
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -20,13 +21,27 @@ var (
 	// ErrLanguageNotFound indicates requested language data is not installed
 	ErrLanguageNotFound = fmt.Errorf("specified language data not found")
 
+	// ErrOSDNotInstalled indicates osd.traineddata, needed for orientation
+	// and script detection, is not installed
+	ErrOSDNotInstalled = fmt.Errorf("OSD language data (osd.traineddata) not found")
+
 	// ErrUnsupportedFormat indicates image format is not supported by Tesseract
 	ErrUnsupportedFormat = fmt.Errorf("unsupported image format")
 
 	// ErrInvalidConfig indicates invalid Tesseract configuration
 	ErrInvalidConfig = fmt.Errorf("invalid Tesseract config")
 
-	// ErrEmptyOutput indicates OCR produced no output
+	// ErrVariableConflict indicates a user variable overrides one the
+	// requested operation depends on
+	ErrVariableConflict = fmt.Errorf("conflicting Tesseract variable")
+
+	// ErrFeatureUnsupported indicates the installed Tesseract is too old
+	// for the requested feature; the error is a *FeatureError
+	ErrFeatureUnsupported = fmt.Errorf("feature not supported by installed Tesseract")
+
+	// ErrEmptyOutput indicates OCR produced no output. It only marks failed
+	// runs: a blank page that tesseract exits 0 for is returned as empty
+	// text with no error.
 	ErrEmptyOutput = fmt.Errorf("OCR produced no output")
 
 	// ErrInvalidOutput indicates OCR output is invalid or corrupted
@@ -40,6 +55,16 @@ type OCRError struct {
 
 	// Stderr contains error output from Tesseract
 	Stderr string
+
+	// Err is the sentinel error Stderr was classified as, such as
+	// ErrLanguageNotFound or ErrInvalidImage, or nil if it was not
+	// recognised
+	Err error
+}
+
+// newOCRError builds the OCRError for a failed run, classifying stderr
+func newOCRError(code int, stderr []byte) *OCRError {
+	return &OCRError{Code: code, Stderr: string(stderr), Err: classifyStderr(string(stderr))}
 }
 
 // Error implements the error interface for OCRError
@@ -51,12 +76,77 @@ func (e *OCRError) Error() string {
 	return fmt.Sprintf("tesseract error (code %d): %s", e.Code, msg)
 }
 
+// Unwrap returns the sentinel error the failure was classified as, so
+// errors.Is(err, ErrLanguageNotFound) and the like work on OCRErrors
+func (e *OCRError) Unwrap() error {
+	return e.Err
+}
+
+// stderrPatterns maps messages tesseract and leptonica print on failure to
+// sentinel errors. The first match wins, so more specific patterns come
+// first.
+var stderrPatterns = []struct {
+	substr string
+	err    error
+}{
+	{"osd.traineddata", ErrOSDNotInstalled},
+	{"Failed loading language 'osd'", ErrOSDNotInstalled},
+	{"Failed loading language", ErrLanguageNotFound},
+	{"Error opening data file", ErrLanguageNotFound},
+	{"couldn't load any languages", ErrLanguageNotFound},
+	{"read_params_file: Can't open", ErrInvalidConfig},
+	{"Could not set option", ErrInvalidConfig},
+	{"Error in pixRead", ErrInvalidImage},
+	{"Error in findFileFormat", ErrInvalidImage},
+	{"image file not found", ErrInvalidImage},
+	{"cannot be read", ErrInvalidImage},
+	{"Unsupported image type", ErrInvalidImage},
+	{"Unknown format", ErrInvalidImage},
+	{"Image too small", ErrInvalidImage},
+	{"Image too large", ErrInvalidImage},
+	{"Empty page!!", ErrEmptyOutput},
+}
+
+// classifyStderr returns the sentinel error matching tesseract's stderr,
+// or nil
+func classifyStderr(stderr string) error {
+	for _, p := range stderrPatterns {
+		if strings.Contains(stderr, p.substr) {
+			return p.err
+		}
+	}
+	return nil
+}
+
+// FeatureError reports a feature that needs a newer Tesseract than the one
+// installed. It matches ErrFeatureUnsupported with errors.Is.
+type FeatureError struct {
+	// Feature names what was requested, e.g. "alto output"
+	Feature string
+
+	// Required is the oldest Tesseract supporting Feature
+	Required Version
+
+	// Installed is the version of the tesseract binary in use
+	Installed Version
+}
+
+// Error implements the error interface for FeatureError
+func (e *FeatureError) Error() string {
+	return fmt.Sprintf("%s requires tesseract %s or newer, found %s", e.Feature, e.Required, e.Installed)
+}
+
+// Unwrap returns ErrFeatureUnsupported
+func (e *FeatureError) Unwrap() error {
+	return ErrFeatureUnsupported
+}
+
 // IsTimeout returns true if the error indicates a timeout
 func IsTimeout(err error) bool {
-	return err == ErrProcessTimeout
+	return errors.Is(err, ErrProcessTimeout)
 }
 
 // IsNotFound returns true if the error indicates Tesseract is not installed
 func IsNotFound(err error) bool {
-	return err == ErrTesseractNotFound
+	return errors.Is(err, ErrTesseractNotFound)
 }
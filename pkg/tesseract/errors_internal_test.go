@@ -0,0 +1,68 @@
+package tesseract
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyStderr(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   error
+	}{
+		{
+			name: "Missing traineddata",
+			stderr: "Error opening data file /usr/share/tesseract-ocr/5/tessdata/deu.traineddata\n" +
+				"Please make sure the TESSDATA_PREFIX environment variable is set to your \"tessdata\" directory.\n" +
+				"Failed loading language 'deu'\nTesseract couldn't load any languages!\nCould not initialize tesseract.\n",
+			want: ErrLanguageNotFound,
+		},
+		{
+			name:   "Missing OSD",
+			stderr: "Error opening data file /usr/share/tessdata/osd.traineddata\nFailed loading language 'osd'\n",
+			want:   ErrOSDNotInstalled,
+		},
+		{
+			name:   "Unreadable image",
+			stderr: "Error in pixReadStream: Unknown format: no pix returned\nError in pixRead: pix not read\nError during processing.\n",
+			want:   ErrInvalidImage,
+		},
+		{
+			name:   "Missing image",
+			stderr: "Error in fopenReadStream: file not found\nImage file scan.png cannot be read!\n",
+			want:   ErrInvalidImage,
+		},
+		{
+			name:   "Empty page",
+			stderr: "Empty page!!\n",
+			want:   ErrEmptyOutput,
+		},
+		{
+			name:   "Blank page and unreadable page",
+			stderr: "Page 1\nEmpty page!!\nPage 2\nError in pixRead: pix not read\nImage file page2.png cannot be read!\n",
+			want:   ErrInvalidImage,
+		},
+		{
+			name:   "Missing config file",
+			stderr: "read_params_file: Can't open myconfig\n",
+			want:   ErrInvalidConfig,
+		},
+		{
+			name:   "Unknown",
+			stderr: "Segmentation fault\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newOCRError(1, []byte(tt.stderr))
+			if err.Err != tt.want {
+				t.Errorf("newOCRError().Err = %v, want %v", err.Err, tt.want)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package tesseract_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestRunErrors(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{ExitCode: 1, Stderr: []byte("Error in pixReadStream: Unknown format: no pix returned\n")})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	_, err := client.ImageToBoxes(testImage(), "")
+	var ocrErr *tesseract.OCRError
+	if !errors.Is(err, tesseract.ErrInvalidImage) || !errors.As(err, &ocrErr) || ocrErr.Code != 1 {
+		t.Errorf("ImageToBoxes() error = %v, want *OCRError matching %v", err, tesseract.ErrInvalidImage)
+	}
+
+	// A binary that disappears after the client found it
+	data, err := os.ReadFile(fakeBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tesseract")
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	missing := newClient(t, tesseract.Config{TesseractPath: path})
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := missing.ImageToString(testImage(), ""); !tesseract.IsNotFound(err) {
+		t.Errorf("ImageToString() with missing binary error = %v, want IsNotFound", err)
+	}
+}
+
+func TestEmptyPage(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+	}{
+		{"Blank image", ""},
+		{"Blank page separators", "\f\f"},
+		{"One blank page of two", "text\n\f\f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// tesseract reports a blank page on stderr but exits 0, which
+			// is a successful run with no text
+			exec := tesseracttest.NewExecutor("5.3.0")
+			exec.Respond(tesseract.Execution{Stdout: []byte(tt.stdout), Stderr: []byte("Empty page!!\n")})
+			client := newClient(t, tesseract.Config{Executor: exec})
+
+			text, err := client.ImageToString(testImage(), "")
+			if err != nil || text != tt.stdout {
+				t.Errorf("ImageToString() = %q, %v; want %q, nil", text, err, tt.stdout)
+			}
+		})
+	}
+}
+
+func TestIsTimeoutWrapped(t *testing.T) {
+	if !tesseract.IsTimeout(fmt.Errorf("page 3: %w", tesseract.ErrProcessTimeout)) {
+		t.Error("IsTimeout() = false for a wrapped ErrProcessTimeout")
+	}
+	if !tesseract.IsNotFound(fmt.Errorf("%w: tesseract", tesseract.ErrTesseractNotFound)) {
+		t.Error("IsNotFound() = false for a wrapped ErrTesseractNotFound")
+	}
+	if tesseract.IsTimeout(context.Canceled) {
+		t.Error("IsTimeout(context.Canceled) = true")
+	}
+}
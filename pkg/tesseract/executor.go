@@ -0,0 +1,129 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Executor runs the tesseract binary on behalf of a Client. Set
+// Config.Executor to replace the default CommandExecutor, for example with
+// the in-memory fake from the tesseracttest package.
+type Executor interface {
+	// Run executes tesseract with args, which exclude the program name,
+	// feeding it stdin if non-nil. It returns a non-nil error only if the
+	// process could not be run to completion; a non-zero exit status is
+	// reported through Execution.ExitCode.
+	Run(ctx context.Context, args []string, stdin io.Reader) (*Execution, error)
+}
+
+// Execution is the outcome of a tesseract run
+type Execution struct {
+	Stdout []byte
+	Stderr []byte
+
+	// ExitCode is the process exit status
+	ExitCode int
+
+	// CommandLine is the program and arguments that were started, such
+	// as a nice(1) wrapper around tesseract; nil if the Executor does not
+	// report it
+	CommandLine []string
+
+	// Files holds the files tesseract wrote next to its output base (the
+	// second argument, unless it is "stdout"), keyed by suffix without the
+	// leading dot, e.g. "tsv" for output.tsv
+	Files map[string][]byte
+}
+
+// CommandExecutor runs tesseract as a child process using os/exec. On
+// cancellation the whole process tree is killed.
+type CommandExecutor struct {
+	// Path is the tesseract binary; empty looks up "tesseract" in PATH
+	Path string
+
+	// Nice runs the process through nice(1) (Linux only)
+	Nice int
+
+	// Env holds extra "NAME=value" environment variables, added to the
+	// environment of the current process
+	Env []string
+}
+
+// Run implements Executor
+func (e *CommandExecutor) Run(ctx context.Context, args []string, stdin io.Reader) (*Execution, error) {
+	path := e.Path
+	if path == "" {
+		path = defaultTesseractCmd
+	}
+
+	name, cmdArgs, err := niceCommand(e.Nice, path, args)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, name, cmdArgs...)
+	killProcessTree(cmd)
+	if len(e.Env) > 0 {
+		cmd.Env = append(os.Environ(), e.Env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	res := &Execution{
+		Stdout:      stdout.Bytes(),
+		Stderr:      stderr.Bytes(),
+		CommandLine: append([]string{name}, cmdArgs...),
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		res.ExitCode = exitErr.ExitCode()
+		err = nil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	if len(args) >= 2 && args[1] != "stdout" && !strings.HasPrefix(args[0], "-") {
+		if res.Files, err = readOutputFiles(args[1]); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// readOutputFiles reads every file named outBase.<suffix>
+func readOutputFiles(outBase string) (map[string][]byte, error) {
+	matches, err := filepath.Glob(outBase + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(matches))
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files[strings.TrimPrefix(path, outBase+".")] = data
+	}
+	return files, nil
+}
+
+// executor returns the configured Executor or a CommandExecutor running b
+func (c *Client) executor(b *binary) Executor {
+	if c.config.Executor != nil {
+		return c.config.Executor
+	}
+	return &CommandExecutor{Path: b.path, Nice: c.config.Nice, Env: c.env}
+}
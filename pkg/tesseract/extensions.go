@@ -5,72 +5,94 @@ import (
 	"context"
 	"fmt"
 	"image"
-	"os"
-	"path/filepath"
+	"strings"
 )
 
 // SupportedExtension represents a supported output format and its configuration
 type SupportedExtension struct {
 	config  string
-	version string // Minimum Tesseract version required
+	version Version // Minimum Tesseract version required
 }
 
 // Supported Tesseract output extensions and their configurations
 var supportedExtensions = map[string]SupportedExtension{
-	"hocr": {"tessedit_create_hocr=1", "3.05"},
-	"xml":  {"tessedit_create_alto=1", "4.1.0"},
-	"tsv":  {"tessedit_create_tsv=1", "3.05"},
-	"pdf":  {"tessedit_create_pdf=1", "3.05"},
+	"hocr": {"tessedit_create_hocr=1", Version{3, 5, 0}},
+	"xml":  {"tessedit_create_alto=1", Version{4, 1, 0}},
+	"tsv":  {"tessedit_create_tsv=1", Version{3, 5, 0}},
+	"pdf":  {"tessedit_create_pdf=1", Version{3, 5, 0}},
 }
 
 // ErrUnsupportedExtension indicates requested output format is not supported
 var ErrUnsupportedExtension = fmt.Errorf("unsupported output extension")
 
 // ImageToExtension performs OCR and returns output in specified format
-func (c *Client) ImageToExtension(img image.Image, lang, extension string) (string, error) {
-	if err := validateImageFormat(img); err != nil {
-		return "", err
+func (c *Client) ImageToExtension(img image.Image, lang, extension string, opts ...Options) (string, error) {
+	return c.ImageToExtensionContext(context.Background(), img, lang, extension, opts...)
+}
+
+// ImageToExtensionContext is like ImageToExtension but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToExtensionContext(ctx context.Context, img image.Image, lang, extension string, opts ...Options) (string, error) {
+	return c.InputToExtensionContext(ctx, ImageInput(img), lang, extension, opts...)
+}
+
+// extension runs tesseract with the renderer for extension and the
+// required variables enabled and returns the contents of the file it wrote
+func (c *Client) extension(ctx context.Context, in Input, lang, extension string, opts []Options, required map[string]string) ([]byte, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
 	}
 
 	extConfig, ok := supportedExtensions[extension]
 	if !ok {
-		return "", fmt.Errorf("%w: %s", ErrUnsupportedExtension, extension)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExtension, extension)
 	}
-
-	ctx := context.Background()
-	if c.config.Timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
-		defer cancel()
+	if err := c.requireVersion(extension+" output", extConfig.version); err != nil {
+		return nil, err
 	}
 
-	tmpDir, err := createTempDir()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req, err := c.newRequest("", lang, opts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer os.RemoveAll(tmpDir)
-
-	outBase := filepath.Join(tmpDir, "output")
-	args := []string{outBase}
 
-	if lang != "" {
-		if err := validateLanguage(lang); err != nil {
-			return "", err
-		}
-		args = append(args, "-l", lang)
+	name, value, _ := strings.Cut(extConfig.config, "=")
+	if err := req.require(name, value); err != nil {
+		return nil, err
 	}
-
-	args = append(args, "-c", extConfig.config)
-
-	if _, err := runOCR(ctx, img, lang, args); err != nil {
-		return "", err
+	for name, value := range required {
+		if err := req.require(name, value); err != nil {
+			return nil, err
+		}
 	}
 
-	outputFile := outBase + "." + extension
-	output, err := os.ReadFile(outputFile)
+	res, err := c.runOCR(ctx, in, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to read output file: %w", err)
+		return nil, err
 	}
 
-	return string(output), nil
+	return readOutput(res.Files, extension)
+}
+
+// outputAliases lists other suffixes tesseract versions have used for an
+// extension's output file
+var outputAliases = map[string][]string{
+	"xml": {"alto"},
+}
+
+// readOutput returns the file tesseract rendered for extension, trying
+// the suffixes in outputAliases when the expected file is missing
+func readOutput(files map[string][]byte, extension string) ([]byte, error) {
+	if output, ok := files[extension]; ok {
+		return output, nil
+	}
+	for _, alias := range outputAliases[extension] {
+		if output, ok := files[alias]; ok {
+			return output, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: tesseract wrote no .%s file", ErrEmptyOutput, extension)
 }
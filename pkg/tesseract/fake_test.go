@@ -0,0 +1,173 @@
+package tesseract_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tessdata"
+)
+
+func TestFakeTesseract(t *testing.T) {
+	client, err := tesseract.NewClient(tesseract.Config{TesseractPath: fakeBinary, Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	img := testImage()
+
+	t.Run("Data", func(t *testing.T) {
+		words, err := client.ImageToData(img, "eng")
+		if err != nil {
+			t.Fatalf("ImageToData() error = %v", err)
+		}
+		if len(words) != 12 {
+			t.Errorf("ImageToData() returned %d records, want 12", len(words))
+		}
+	})
+
+	t.Run("Orientation", func(t *testing.T) {
+		osd, err := client.DetectOrientation(img)
+		if err != nil {
+			t.Fatalf("DetectOrientation() error = %v", err)
+		}
+		if osd.Script != "Latin" {
+			t.Errorf("DetectOrientation() script = %q, want Latin", osd.Script)
+		}
+	})
+
+	t.Run("Recognize", func(t *testing.T) {
+		res, err := client.Recognize(img, "eng", []tesseract.Format{
+			tesseract.FormatText, tesseract.FormatTSV, tesseract.FormatHOCR,
+		})
+		if err != nil {
+			t.Fatalf("Recognize() error = %v", err)
+		}
+		if res.Text == "" || len(res.Words) == 0 || res.HOCR == nil {
+			t.Errorf("Recognize() returned incomplete result: %+v", res)
+		}
+	})
+
+	t.Run("Boxes", func(t *testing.T) {
+		boxes, err := client.ImageToBoxes(img, "eng")
+		if err != nil {
+			t.Fatalf("ImageToBoxes() error = %v", err)
+		}
+		if len(boxes) != 5 || boxes[0].Char != 'H' {
+			t.Errorf("ImageToBoxes() = %+v", boxes)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		words, err := client.FileToData("testdata/sample.png", "eng")
+		if err != nil || len(words) == 0 {
+			t.Errorf("FileToData() = %d records, %v", len(words), err)
+		}
+	})
+
+	t.Run("MissingLanguage", func(t *testing.T) {
+		if _, err := client.ImageToString(img, "deu"); !errors.Is(err, tesseract.ErrLanguageNotFound) {
+			t.Errorf("ImageToString() error = %v, want %v", err, tesseract.ErrLanguageNotFound)
+		}
+	})
+}
+
+func TestFakeTessdataDir(t *testing.T) {
+	// Tesseract 3 does not name the tessdata directory in --list-langs
+	for _, version := range []string{"5.3.0", "3.05.02"} {
+		t.Run(version, func(t *testing.T) {
+			useFixtures(t, map[string]string{"version.txt": "tesseract " + version + "\n leptonica-1.82.0\n"})
+			testTessdataDir(t)
+		})
+	}
+}
+
+func testTessdataDir(t *testing.T) {
+	dir := t.TempDir()
+	install := func(name string) {
+		t.Helper()
+		path := filepath.Join(dir, name+".traineddata")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Make the change visible on file systems with coarse timestamps
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(filepath.Dir(path), later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	install("eng")
+
+	client, err := tesseract.NewClient(tesseract.Config{TesseractPath: fakeBinary, TessdataDir: dir})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if got, err := client.TessdataDir(); err != nil || got != dir {
+		t.Errorf("TessdataDir() = %q, %v, want %q", got, err, dir)
+	}
+	if err := client.CheckLanguages("eng+fra"); !errors.Is(err, tesseract.ErrLanguageNotFound) {
+		t.Errorf("CheckLanguages(eng+fra) error = %v, want %v", err, tesseract.ErrLanguageNotFound)
+	}
+
+	// A new file in the tessdata directory invalidates the cached list
+	install("fra")
+	langs, err := client.GetAvailableLanguages()
+	if want := []string{"eng", "fra"}; err != nil || !reflect.DeepEqual(langs, want) {
+		t.Errorf("GetAvailableLanguages() after install = %q, %v, want %q", langs, err, want)
+	}
+	if _, err := client.ImageToBoxes(testImage(), "eng+fra"); err != nil {
+		t.Errorf("ImageToBoxes(eng+fra) after install error = %v", err)
+	}
+
+	install("script/Latin")
+	langs, err = client.RefreshLanguages()
+	if want := []string{"eng", "fra", "script/Latin"}; err != nil || !reflect.DeepEqual(langs, want) {
+		t.Errorf("RefreshLanguages() = %q, %v, want %q", langs, err, want)
+	}
+}
+
+func TestFakeAutoInstall(t *testing.T) {
+	src := t.TempDir()
+	pack := filepath.Join(src, tessdata.Fast.Repository(), "deu.traineddata")
+	if err := os.MkdirAll(filepath.Dir(pack), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pack, []byte("deu"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "eng.traineddata"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := tessdata.NewManager(tessdata.Config{
+		Dir:             dir,
+		Source:          tessdata.Dir(src),
+		AllowUnverified: true,
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	client, err := tesseract.NewClient(tesseract.Config{TesseractPath: fakeBinary, TessdataDir: dir, Installer: manager})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, err := client.ImageToString(testImage(), "eng+deu"); err != nil {
+		t.Fatalf("ImageToString(eng+deu) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "deu.traineddata")); err != nil {
+		t.Errorf("deu was not installed: %v", err)
+	}
+
+	if _, err := client.ImageToBoxes(testImage(), "eng+fra"); !errors.Is(err, tessdata.ErrNotFound) {
+		t.Errorf("ImageToBoxes(eng+fra) error = %v, want %v", err, tessdata.ErrNotFound)
+	}
+}
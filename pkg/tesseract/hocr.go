@@ -0,0 +1,25 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bytes"
+	"context"
+	"image"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract/hocr"
+)
+
+// ImageToHOCR performs OCR and returns the hOCR output as a document tree
+func (c *Client) ImageToHOCR(img image.Image, lang string, opts ...Options) (*hocr.Document, error) {
+	return c.ImageToHOCRContext(context.Background(), img, lang, opts...)
+}
+
+// ImageToHOCRContext is like ImageToHOCR but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToHOCRContext(ctx context.Context, img image.Image, lang string, opts ...Options) (*hocr.Document, error) {
+	out, err := c.extension(ctx, ImageInput(img), lang, "hocr", opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return hocr.Parse(bytes.NewReader(out))
+}
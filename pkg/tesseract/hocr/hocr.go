@@ -0,0 +1,369 @@
+// Package hocr parses the hOCR documents produced by Tesseract into a
+// Page → Area → Paragraph → Line → Word tree
+package hocr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Document is a parsed hOCR file
+type Document struct {
+	Pages []Page
+}
+
+// Page is an ocr_page element
+type Page struct {
+	ID string
+
+	// Image is the source image path recorded by Tesseract
+	Image string
+
+	// Number is the zero-based physical page number (ppageno)
+	Number int
+
+	BBox  image.Rectangle
+	Areas []Area
+}
+
+// Area is a content area such as ocr_carea, ocr_photo or ocr_separator
+type Area struct {
+	ID         string
+	Class      string
+	BBox       image.Rectangle
+	Paragraphs []Paragraph
+}
+
+// Paragraph is an ocr_par element
+type Paragraph struct {
+	ID   string
+	BBox image.Rectangle
+
+	// Language is the paragraph's lang attribute, e.g. "eng"
+	Language string
+
+	Lines []Line
+}
+
+// Line is a text line: ocr_line, ocr_header, ocr_caption or ocr_textfloat
+type Line struct {
+	ID    string
+	Class string
+	BBox  image.Rectangle
+
+	// Baseline is the line's baseline relative to the bottom-left corner
+	// of BBox
+	Baseline Baseline
+
+	// XSize is the line height in pixels
+	XSize float64
+
+	// XDescenders is the descender height in pixels
+	XDescenders float64
+
+	// XAscenders is the ascender height in pixels
+	XAscenders float64
+
+	Words []Word
+}
+
+// Baseline describes a line's baseline as y = Slope*x + Offset
+type Baseline struct {
+	Slope  float64
+	Offset float64
+}
+
+// Word is an ocrx_word element
+type Word struct {
+	ID   string
+	BBox image.Rectangle
+
+	// Confidence is the word confidence in [0, 100] (x_wconf)
+	Confidence float64
+
+	// Language is the word's lang attribute; Tesseract only sets it when
+	// it differs from the paragraph
+	Language string
+
+	Text string
+}
+
+// Text joins the words of the line with spaces
+func (l Line) Text() string {
+	words := make([]string, len(l.Words))
+	for i, w := range l.Words {
+		words[i] = w.Text
+	}
+	return strings.Join(words, " ")
+}
+
+// Text joins the lines of the paragraph with newlines
+func (p Paragraph) Text() string {
+	lines := make([]string, len(p.Lines))
+	for i, l := range p.Lines {
+		lines[i] = l.Text()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Text joins the paragraphs of the area with blank lines
+func (a Area) Text() string {
+	pars := make([]string, len(a.Paragraphs))
+	for i, p := range a.Paragraphs {
+		pars[i] = p.Text()
+	}
+	return strings.Join(pars, "\n\n")
+}
+
+// element kinds tracked while walking the document
+const (
+	kindOther = iota
+	kindPage
+	kindArea
+	kindParagraph
+	kindLine
+	kindWord
+)
+
+func classKind(class string) int {
+	for _, c := range strings.Fields(class) {
+		switch c {
+		case "ocr_page":
+			return kindPage
+		case "ocr_carea", "ocr_photo", "ocr_separator":
+			return kindArea
+		case "ocr_par":
+			return kindParagraph
+		case "ocr_line", "ocr_header", "ocr_caption", "ocr_textfloat":
+			return kindLine
+		case "ocrx_word":
+			return kindWord
+		}
+	}
+	return kindOther
+}
+
+// Parse reads an hOCR document from r
+func Parse(r io.Reader) (*Document, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	doc := &Document{}
+	var stack []int
+	var text strings.Builder
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("hocr: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			attrs := attributes(t)
+			kind := classKind(attrs["class"])
+			stack = append(stack, kind)
+			if kind == kindOther {
+				continue
+			}
+
+			props, err := parseTitle(attrs["title"])
+			if err != nil {
+				return nil, fmt.Errorf("hocr: %s %q: %w", attrs["class"], attrs["id"], err)
+			}
+			switch kind {
+			case kindPage:
+				doc.Pages = append(doc.Pages, Page{
+					ID:     attrs["id"],
+					Image:  props.image,
+					Number: props.ppageno,
+					BBox:   props.bbox,
+				})
+			case kindArea:
+				page := doc.page()
+				page.Areas = append(page.Areas, Area{
+					ID:    attrs["id"],
+					Class: strings.Fields(attrs["class"])[0],
+					BBox:  props.bbox,
+				})
+			case kindParagraph:
+				area := doc.page().area()
+				area.Paragraphs = append(area.Paragraphs, Paragraph{
+					ID:       attrs["id"],
+					BBox:     props.bbox,
+					Language: attrs["lang"],
+				})
+			case kindLine:
+				par := doc.page().area().paragraph()
+				par.Lines = append(par.Lines, Line{
+					ID:          attrs["id"],
+					Class:       strings.Fields(attrs["class"])[0],
+					BBox:        props.bbox,
+					Baseline:    props.baseline,
+					XSize:       props.xSize,
+					XDescenders: props.xDescenders,
+					XAscenders:  props.xAscenders,
+				})
+			case kindWord:
+				line := doc.page().area().paragraph().line()
+				line.Words = append(line.Words, Word{
+					ID:         attrs["id"],
+					BBox:       props.bbox,
+					Confidence: props.xWconf,
+					Language:   attrs["lang"],
+				})
+				text.Reset()
+			}
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			kind := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if kind == kindWord {
+				line := doc.page().area().paragraph().line()
+				line.Words[len(line.Words)-1].Text = strings.TrimSpace(text.String())
+			}
+
+		case xml.CharData:
+			for _, kind := range stack {
+				if kind == kindWord {
+					text.Write(t)
+					break
+				}
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// page returns the last page, creating one if the document has none
+func (d *Document) page() *Page {
+	if len(d.Pages) == 0 {
+		d.Pages = append(d.Pages, Page{})
+	}
+	return &d.Pages[len(d.Pages)-1]
+}
+
+// area returns the last area, creating one if the page has none
+func (p *Page) area() *Area {
+	if len(p.Areas) == 0 {
+		p.Areas = append(p.Areas, Area{Class: "ocr_carea"})
+	}
+	return &p.Areas[len(p.Areas)-1]
+}
+
+// paragraph returns the last paragraph, creating one if the area has none
+func (a *Area) paragraph() *Paragraph {
+	if len(a.Paragraphs) == 0 {
+		a.Paragraphs = append(a.Paragraphs, Paragraph{})
+	}
+	return &a.Paragraphs[len(a.Paragraphs)-1]
+}
+
+// line returns the last line, creating one if the paragraph has none
+func (p *Paragraph) line() *Line {
+	if len(p.Lines) == 0 {
+		p.Lines = append(p.Lines, Line{Class: "ocr_line"})
+	}
+	return &p.Lines[len(p.Lines)-1]
+}
+
+func attributes(el xml.StartElement) map[string]string {
+	attrs := make(map[string]string, len(el.Attr))
+	for _, a := range el.Attr {
+		attrs[a.Name.Local] = a.Value
+	}
+	return attrs
+}
+
+// properties holds the title attribute values this package understands
+type properties struct {
+	bbox        image.Rectangle
+	baseline    Baseline
+	image       string
+	ppageno     int
+	xSize       float64
+	xDescenders float64
+	xAscenders  float64
+	xWconf      float64
+}
+
+// parseTitle parses an hOCR title such as
+// "bbox 36 92 582 127; baseline 0.002 -7; x_size 35; x_wconf 96"
+func parseTitle(title string) (properties, error) {
+	var p properties
+	for _, prop := range strings.Split(title, ";") {
+		fields := strings.Fields(prop)
+		if len(fields) == 0 {
+			continue
+		}
+		name, args := fields[0], fields[1:]
+
+		var n []float64
+		var err error
+		switch name {
+		case "bbox":
+			if n, err = numbers(args, 4); err == nil {
+				p.bbox = image.Rect(int(n[0]), int(n[1]), int(n[2]), int(n[3]))
+			}
+		case "baseline":
+			if n, err = numbers(args, 2); err == nil {
+				p.baseline = Baseline{Slope: n[0], Offset: n[1]}
+			}
+		case "image":
+			p.image = strings.Trim(strings.Join(args, " "), `"`)
+		case "ppageno":
+			if n, err = numbers(args, 1); err == nil {
+				p.ppageno = int(n[0])
+			}
+		case "x_size":
+			if n, err = numbers(args, 1); err == nil {
+				p.xSize = n[0]
+			}
+		case "x_descenders":
+			if n, err = numbers(args, 1); err == nil {
+				p.xDescenders = n[0]
+			}
+		case "x_ascenders":
+			if n, err = numbers(args, 1); err == nil {
+				p.xAscenders = n[0]
+			}
+		case "x_wconf":
+			if n, err = numbers(args, 1); err == nil {
+				p.xWconf = n[0]
+			}
+		}
+		if err != nil {
+			return p, fmt.Errorf("bad %s %q", name, strings.Join(args, " "))
+		}
+	}
+	return p, nil
+}
+
+// numbers parses exactly count numeric arguments
+func numbers(args []string, count int) ([]float64, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("want %d values, got %d", count, len(args))
+	}
+	n := make([]float64, count)
+	for i, a := range args {
+		v, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+	}
+	return n, nil
+}
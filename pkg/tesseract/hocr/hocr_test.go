@@ -0,0 +1,98 @@
+package hocr
+
+import (
+	"image"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	f, err := os.Open("testdata/sample.hocr")
+	if err != nil {
+		t.Fatalf("Failed to open sample: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := &Document{Pages: []Page{{
+		ID:     "page_1",
+		Image:  "/tmp/tesseract_1/input.png",
+		Number: 0,
+		BBox:   image.Rect(0, 0, 640, 200),
+		Areas: []Area{
+			{
+				ID:    "block_1_1",
+				Class: "ocr_carea",
+				BBox:  image.Rect(36, 20, 604, 127),
+				Paragraphs: []Paragraph{{
+					ID:       "par_1_1",
+					BBox:     image.Rect(36, 20, 604, 127),
+					Language: "eng",
+					Lines: []Line{
+						{
+							ID:          "line_1_1",
+							Class:       "ocr_line",
+							BBox:        image.Rect(36, 20, 604, 60),
+							Baseline:    Baseline{Slope: 0.002, Offset: -7},
+							XSize:       35,
+							XDescenders: 7,
+							XAscenders:  9,
+							Words: []Word{
+								{ID: "word_1_1", BBox: image.Rect(36, 20, 180, 60), Confidence: 96, Text: "Fish&Chips"},
+								{ID: "word_1_2", BBox: image.Rect(200, 22, 604, 58), Confidence: 91, Text: "£4.50"},
+							},
+						},
+						{
+							ID:          "line_1_2",
+							Class:       "ocr_header",
+							BBox:        image.Rect(36, 80, 300, 127),
+							Baseline:    Baseline{Slope: 0, Offset: -9},
+							XSize:       40,
+							XDescenders: 9,
+							XAscenders:  10,
+							Words: []Word{
+								{ID: "word_1_3", BBox: image.Rect(36, 80, 300, 127), Confidence: 88, Language: "fra", Text: "Merci"},
+							},
+						},
+					},
+				}},
+			},
+			{
+				ID:    "block_1_2",
+				Class: "ocr_photo",
+				BBox:  image.Rect(400, 140, 600, 190),
+			},
+		},
+	}}}
+
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Parse() = %+v\nwant %+v", doc, want)
+	}
+	if got := doc.Pages[0].Areas[0].Text(); got != "Fish&Chips £4.50\nMerci" {
+		t.Errorf("Area.Text() = %q", got)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"Bad bbox", `<div class='ocr_page' title='bbox 0 0 x 1'></div>`},
+		{"Short baseline", `<span class='ocr_line' title='bbox 0 0 1 1; baseline 0.1'></span>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.data)); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inputKind tells which field of an Input holds the image
+type inputKind int
+
+const (
+	inputNone inputKind = iota
+	inputImage
+	inputFile
+	inputBytes
+	inputReader
+	inputList
+)
+
+// Input is an image to recognise. Encoded images (files, byte slices and
+// readers) are handed to tesseract untouched, keeping metadata such as the
+// resolution that re-encoding an image.Image would lose. Their format is
+// sniffed from the leading bytes and must be one tesseract reads.
+type Input struct {
+	kind   inputKind
+	img    image.Image
+	path   string
+	data   []byte
+	reader io.Reader
+	format string
+	list   []Input
+}
+
+// ImageInput wraps a decoded image; it is encoded as PNG for tesseract
+func ImageInput(img image.Image) Input {
+	return Input{kind: inputImage, img: img}
+}
+
+// FileInput reads the image file at path
+func FileInput(path string) Input {
+	return Input{kind: inputFile, path: path}
+}
+
+// BytesInput reads an encoded image from data. format ("jpeg", "tiff", ...)
+// is optional and, when given, must match the data.
+func BytesInput(data []byte, format string) Input {
+	return Input{kind: inputBytes, data: data, format: format}
+}
+
+// ReaderInput reads an encoded image from r. format is optional as for
+// BytesInput. r is read at most once.
+func ReaderInput(r io.Reader, format string) Input {
+	return Input{kind: inputReader, reader: r, format: format}
+}
+
+// ListInput combines inputs into an image list file, which tesseract
+// recognises as consecutive pages of one document. Encoded inputs may be
+// multi-page TIFFs themselves.
+func ListInput(inputs ...Input) Input {
+	return Input{kind: inputList, list: inputs}
+}
+
+// validate checks the input is set without reading it
+func (in Input) validate() error {
+	switch in.kind {
+	case inputImage:
+		return validateImageFormat(in.img)
+	case inputFile:
+		if in.path == "" {
+			return fmt.Errorf("%w: empty path", ErrInvalidImage)
+		}
+	case inputBytes:
+		if len(in.data) == 0 {
+			return fmt.Errorf("%w: empty data", ErrInvalidImage)
+		}
+	case inputReader:
+		if in.reader == nil {
+			return fmt.Errorf("%w: nil reader", ErrInvalidImage)
+		}
+	case inputList:
+		if len(in.list) == 0 {
+			return fmt.Errorf("%w: no images", ErrInvalidImage)
+		}
+		for _, item := range in.list {
+			if err := item.validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrInvalidImage
+	}
+	return nil
+}
+
+// needsFile reports whether in has to be written to a temporary file
+func (in Input) needsFile(useStdin bool) bool {
+	return in.kind == inputList || (in.kind != inputFile && !useStdin)
+}
+
+// source makes in available to tesseract. It returns the image argument
+// and the process stdin; files are written to dir as name.<format>. The
+// returned close function must be called once tesseract has exited.
+func (in Input) source(dir, name string, useStdin bool) (string, io.Reader, func(), error) {
+	noop := func() {}
+
+	switch in.kind {
+	case inputImage:
+		if !useStdin {
+			path := filepath.Join(dir, name+".png")
+			return path, nil, noop, saveImage(path, in.img)
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(png.Encode(pw, in.img))
+		}()
+		// Closing unblocks the encoder if tesseract exits without reading
+		// the whole image
+		return "stdin", pr, func() { pr.Close() }, nil
+
+	case inputFile:
+		f, err := os.Open(in.path)
+		if err != nil {
+			return "", nil, noop, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+		}
+		head := make([]byte, sniffLen)
+		n, err := io.ReadFull(f, head)
+		f.Close()
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return "", nil, noop, err
+		}
+		if _, err := sniffFormat(head[:n], ""); err != nil {
+			return "", nil, noop, fmt.Errorf("%s: %w", in.path, err)
+		}
+		return in.path, nil, noop, nil
+
+	case inputBytes:
+		format, err := sniffFormat(in.data, in.format)
+		if err != nil {
+			return "", nil, noop, err
+		}
+		if useStdin {
+			return "stdin", bytes.NewReader(in.data), noop, nil
+		}
+		path := filepath.Join(dir, name+"."+format)
+		return path, nil, noop, os.WriteFile(path, in.data, 0644)
+
+	case inputReader:
+		br := bufio.NewReader(in.reader)
+		head, err := br.Peek(sniffLen)
+		if err != nil && err != io.EOF {
+			return "", nil, noop, err
+		}
+		format, err := sniffFormat(head, in.format)
+		if err != nil {
+			return "", nil, noop, err
+		}
+		if useStdin {
+			return "stdin", br, noop, nil
+		}
+		path := filepath.Join(dir, name+"."+format)
+		return path, nil, noop, writeFile(path, br)
+
+	case inputList:
+		// The list names files, so none of its images can use stdin
+		var paths []string
+		for i, item := range in.list {
+			path, _, closeItem, err := item.source(dir, fmt.Sprintf("%s-%04d", name, i+1), false)
+			closeItem()
+			if err != nil {
+				return "", nil, noop, err
+			}
+			paths = append(paths, path)
+		}
+		path := filepath.Join(dir, name+".txt")
+		return path, nil, noop, os.WriteFile(path, []byte(strings.Join(paths, "\n")+"\n"), 0644)
+	}
+	return "", nil, noop, ErrInvalidImage
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sniffLen is the number of leading bytes sniffFormat needs
+const sniffLen = 12
+
+// formatAliases maps alternative names in supportedFormats to the name
+// sniffFormat reports
+var formatAliases = map[string]string{"jpg": "jpeg", "tif": "tiff"}
+
+// sniffFormat detects the image format from the magic bytes at the start
+// of data and checks it against hint and supportedFormats. An empty hint
+// accepts any supported format; data of unknown format is only accepted
+// with a hint.
+func sniffFormat(data []byte, hint string) (string, error) {
+	hint = strings.ToLower(strings.TrimPrefix(hint, "."))
+	if alias, ok := formatAliases[hint]; ok {
+		hint = alias
+	}
+	if hint != "" && !supportedFormats[hint] {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, hint)
+	}
+
+	format := detectFormat(data)
+	switch {
+	case format == "" && hint == "":
+		return "", fmt.Errorf("%w: unrecognized image data", ErrUnsupportedFormat)
+	case format == "":
+		return hint, nil
+	case hint != "" && hint != format:
+		return "", fmt.Errorf("%w: data is %s, not %s", ErrUnsupportedFormat, format, hint)
+	case !supportedFormats[format]:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return format, nil
+}
+
+// detectFormat returns the format named by the magic bytes of data, or ""
+func detectFormat(data []byte) string {
+	has := func(prefix string) bool { return bytes.HasPrefix(data, []byte(prefix)) }
+	switch {
+	case has("\x89PNG\r\n\x1a\n"):
+		return "png"
+	case has("\xff\xd8\xff"):
+		return "jpeg"
+	case has("II*\x00"), has("MM\x00*"):
+		return "tiff"
+	case has("GIF87a"), has("GIF89a"):
+		return "gif"
+	case has("BM") && isBMP(data):
+		return "bmp"
+	case has("RIFF") && len(data) >= 12 && string(data[8:12]) == "WEBP":
+		return "webp"
+	case len(data) >= 3 && data[0] == 'P' && isSpace(data[2]):
+		switch data[1] {
+		case '1', '4':
+			return "pbm"
+		case '2', '5':
+			return "pgm"
+		case '3', '6':
+			return "ppm"
+		}
+	}
+	return ""
+}
+
+// isBMP checks the header of data, which starts with "BM": "BM" alone is
+// too common at the start of text to identify a bitmap
+func isBMP(data []byte) bool {
+	if len(data) < 18 {
+		return false
+	}
+	// The DIB header that follows the 14-byte file header starts with its
+	// own size, which identifies its version
+	size := uint32(data[14]) | uint32(data[15])<<8 | uint32(data[16])<<16 | uint32(data[17])<<24
+	switch size {
+	case 12, 16, 40, 52, 56, 64, 108, 124:
+		return true
+	}
+	return false
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// FileToString performs OCR on the image file at path without decoding it
+func (c *Client) FileToString(path, lang string, opts ...Options) (string, error) {
+	return c.InputToStringContext(context.Background(), FileInput(path), lang, opts...)
+}
+
+// FileToData performs OCR on the image file at path and returns its
+// word-level records, see ImageToData
+func (c *Client) FileToData(path, lang string, opts ...Options) ([]Word, error) {
+	return c.InputToDataContext(context.Background(), FileInput(path), lang, opts...)
+}
+
+// InputToString performs OCR on in and returns the extracted text
+func (c *Client) InputToString(in Input, lang string, opts ...Options) (string, error) {
+	return c.InputToStringContext(context.Background(), in, lang, opts...)
+}
+
+// InputToStringContext is like InputToString but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) InputToStringContext(ctx context.Context, in Input, lang string, opts ...Options) (string, error) {
+	if err := in.validate(); err != nil {
+		return "", err
+	}
+
+	req, err := c.newRequest("stdout", lang, opts)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.runOCR(ctx, in, req)
+	if err != nil {
+		return "", err
+	}
+	return string(res.Stdout), nil
+}
+
+// InputToData performs OCR on in and returns its word-level records, see
+// ImageToData
+func (c *Client) InputToData(in Input, lang string, opts ...Options) ([]Word, error) {
+	return c.InputToDataContext(context.Background(), in, lang, opts...)
+}
+
+// InputToDataContext is like InputToData but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) InputToDataContext(ctx context.Context, in Input, lang string, opts ...Options) ([]Word, error) {
+	out, err := c.extension(ctx, in, lang, "tsv", opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseWords(string(out))
+}
+
+// InputToExtension performs OCR on in and returns output in the specified
+// format, see ImageToExtension
+func (c *Client) InputToExtension(in Input, lang, extension string, opts ...Options) (string, error) {
+	return c.InputToExtensionContext(context.Background(), in, lang, extension, opts...)
+}
+
+// InputToExtensionContext is like InputToExtension but stops the
+// tesseract process when ctx is cancelled or its deadline expires
+func (c *Client) InputToExtensionContext(ctx context.Context, in Input, lang, extension string, opts ...Options) (string, error) {
+	output, err := c.extension(ctx, in, lang, extension, opts, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,51 @@
+package tesseract
+
+import (
+	"errors"
+	"testing"
+)
+
+// jpegHeader is the start of a JFIF file
+const jpegHeader = "\xff\xd8\xff\xe0\x00\x10JFIF\x00\x01\x01\x01\x01\x2c\x01\x2c"
+
+// bmpHeader is a BMP file header followed by the size of a
+// BITMAPINFOHEADER
+const bmpHeader = "BM\x36\x00\x00\x00\x00\x00\x00\x00\x36\x00\x00\x00\x28\x00\x00\x00"
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		hint    string
+		want    string
+		wantErr error
+	}{
+		{name: "PNG", data: "\x89PNG\r\n\x1a\n\x00\x00", want: "png"},
+		{name: "JPEG", data: jpegHeader, want: "jpeg"},
+		{name: "JPEG with jpg hint", data: jpegHeader, hint: ".JPG", want: "jpeg"},
+		{name: "TIFF little endian", data: "II*\x00\x08\x00", want: "tiff"},
+		{name: "TIFF big endian", data: "MM\x00*\x00\x08", hint: "tif", want: "tiff"},
+		{name: "GIF", data: "GIF89a\x01\x00", want: "gif"},
+		{name: "BMP", data: bmpHeader, want: "bmp"},
+		{name: "Text starting with BM", data: "BMX service schedule\n", wantErr: ErrUnsupportedFormat},
+		{name: "Short BM", data: "BM\x36\x00", wantErr: ErrUnsupportedFormat},
+		{name: "WebP", data: "RIFF\x24\x00\x00\x00WEBPVP8 ", want: "webp"},
+		{name: "PGM", data: "P5\n8 8\n255\n", want: "pgm"},
+		{name: "Unknown with hint", data: "\x00\x01\x02", hint: "pbm", want: "pbm"},
+		{name: "Unknown", data: "hello", wantErr: ErrUnsupportedFormat},
+		{name: "Hint mismatch", data: jpegHeader, hint: "png", wantErr: ErrUnsupportedFormat},
+		{name: "Unsupported hint", data: "%PDF-1.5", hint: "pdf", wantErr: ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sniffFormat([]byte(tt.data), tt.hint)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("sniffFormat() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sniffFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
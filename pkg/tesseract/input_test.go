@@ -0,0 +1,97 @@
+package tesseract_test
+
+import (
+	"bytes"
+	"errors"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// jpegData returns testImage encoded as JPEG
+func jpegData(t testing.TB) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInputPassthrough(t *testing.T) {
+	data := jpegData(t)
+	path := filepath.Join(t.TempDir(), "scan.jpg")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		in      tesseract.Input
+		stdin   bool
+		wantArg string
+	}{
+		{name: "File", in: tesseract.FileInput(path), wantArg: path},
+		{name: "Bytes", in: tesseract.BytesInput(data, "jpeg"), wantArg: "input.jpeg"},
+		{name: "Bytes on stdin", in: tesseract.BytesInput(data, ""), stdin: true, wantArg: "stdin"},
+		{name: "Reader", in: tesseract.ReaderInput(bytes.NewReader(data), ""), wantArg: "input.jpeg"},
+		{name: "Reader on stdin", in: tesseract.ReaderInput(bytes.NewReader(data), "jpg"), stdin: true, wantArg: "stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arg string
+			var image []byte
+			exec := tesseracttest.NewExecutor("5.3.0")
+			exec.RespondFunc(func(call tesseracttest.Call) tesseract.Execution {
+				arg, image = call.Args[0], call.Stdin
+				if arg != "stdin" {
+					image, _ = os.ReadFile(arg)
+				}
+				return tesseract.Execution{Stdout: []byte("text\n")}
+			})
+			client := newClient(t, tesseract.Config{Executor: exec, UseStdin: tt.stdin})
+
+			text, err := client.InputToString(tt.in, "")
+			if err != nil || text != "text\n" {
+				t.Fatalf("InputToString() = %q, %v", text, err)
+			}
+			if arg != tt.wantArg && filepath.Base(arg) != tt.wantArg {
+				t.Errorf("image argument = %q, want %q", arg, tt.wantArg)
+			}
+			if !bytes.Equal(image, data) {
+				t.Errorf("tesseract read %q, want the input unchanged", image)
+			}
+		})
+	}
+}
+
+func TestInputInvalid(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Stdout: []byte("text\n")})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name    string
+		in      tesseract.Input
+		wantErr error
+	}{
+		{name: "Zero input", in: tesseract.Input{}, wantErr: tesseract.ErrInvalidImage},
+		{name: "Missing file", in: tesseract.FileInput(filepath.Join(t.TempDir(), "missing.png")), wantErr: tesseract.ErrInvalidImage},
+		{name: "Empty bytes", in: tesseract.BytesInput(nil, "png"), wantErr: tesseract.ErrInvalidImage},
+		{name: "Not an image", in: tesseract.BytesInput([]byte("%PDF-1.5"), ""), wantErr: tesseract.ErrUnsupportedFormat},
+		{name: "Wrong hint", in: tesseract.ReaderInput(bytes.NewReader(jpegData(t)), "tiff"), wantErr: tesseract.ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.InputToString(tt.in, ""); !errors.Is(err, tt.wantErr) {
+				t.Errorf("InputToString() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Languages is a parsed tesseract language spec such as "eng+fra" or
+// "script/Latin+eng". Each element names one traineddata file, relative to
+// the tessdata directory and without the extension. A leading "~" marks a
+// language tesseract should not load, as in "eng+~fra".
+type Languages []string
+
+// ParseLanguages splits a "+"-joined language spec into its components
+func ParseLanguages(spec string) (Languages, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("%w: empty language", ErrInvalidConfig)
+	}
+
+	parts := strings.Split(spec, "+")
+	langs := make(Languages, 0, len(parts))
+	for _, p := range parts {
+		p = strings.ReplaceAll(p, "\\", "/")
+		name := strings.TrimPrefix(p, "~")
+		if name == "" || strings.ContainsAny(name, " \t\r\n~") ||
+			strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") ||
+			strings.Contains(name, "..") {
+			return nil, fmt.Errorf("%w: invalid language %q in %q", ErrInvalidConfig, p, spec)
+		}
+		langs = append(langs, p)
+	}
+	return langs, nil
+}
+
+// String returns the spec as passed to tesseract -l
+func (l Languages) String() string {
+	return strings.Join(l, "+")
+}
+
+// Missing returns the components of l that are not in available, the
+// output of GetAvailableLanguages. Excluded ("~") components are never
+// missing.
+func (l Languages) Missing(available []string) []string {
+	installed := make(map[string]bool, len(available))
+	for _, a := range available {
+		installed[a] = true
+	}
+
+	var missing []string
+	for _, lang := range l {
+		if strings.HasPrefix(lang, "~") {
+			continue
+		}
+		if !installed[lang] {
+			missing = append(missing, lang)
+		}
+	}
+	return missing
+}
+
+// LanguageError lists the components of a language spec whose
+// traineddata is not installed. It matches ErrLanguageNotFound with
+// errors.Is.
+type LanguageError struct {
+	// Missing are the components that are not installed
+	Missing []string
+}
+
+func (e *LanguageError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrLanguageNotFound, strings.Join(e.Missing, ", "))
+}
+
+// Unwrap returns ErrLanguageNotFound
+func (e *LanguageError) Unwrap() error {
+	return ErrLanguageNotFound
+}
+
+// CheckLanguages reports whether every component of the language spec is
+// installed, returning a *LanguageError naming the missing ones
+func (c *Client) CheckLanguages(spec string) error {
+	langs, err := ParseLanguages(spec)
+	if err != nil {
+		return err
+	}
+	available, err := c.GetAvailableLanguages()
+	if err != nil {
+		return err
+	}
+	if missing := langs.Missing(available); len(missing) > 0 {
+		return &LanguageError{Missing: missing}
+	}
+	return nil
+}
+
+// Installer installs traineddata files. *tessdata.Manager implements it.
+type Installer interface {
+	// Install installs the given languages, e.g. "deu" or "script/Latin"
+	Install(ctx context.Context, langs ...string) error
+}
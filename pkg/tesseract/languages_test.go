@@ -0,0 +1,125 @@
+package tesseract_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestParseLanguages(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    tesseract.Languages
+		wantErr bool
+	}{
+		{spec: "eng", want: tesseract.Languages{"eng"}},
+		{spec: "eng+fra", want: tesseract.Languages{"eng", "fra"}},
+		{spec: "script/Latin+eng", want: tesseract.Languages{"script/Latin", "eng"}},
+		{spec: `script\Cyrillic`, want: tesseract.Languages{"script/Cyrillic"}},
+		{spec: "eng+~fra", want: tesseract.Languages{"eng", "~fra"}},
+		{spec: "", wantErr: true},
+		{spec: "eng+", wantErr: true},
+		{spec: "eng++fra", wantErr: true},
+		{spec: "eng fra", wantErr: true},
+		{spec: "script/", wantErr: true},
+		{spec: "../eng", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := tesseract.ParseLanguages(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, tesseract.ErrInvalidConfig) {
+					t.Errorf("ParseLanguages(%q) error = %v, want %v", tt.spec, err, tesseract.ErrInvalidConfig)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLanguages(%q) error = %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLanguages(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+			if s := got.String(); s != strings.ReplaceAll(tt.spec, `\`, "/") {
+				t.Errorf("String() = %q, want %q", s, tt.spec)
+			}
+		})
+	}
+}
+
+func TestLanguagesMissing(t *testing.T) {
+	available := []string{"eng", "fra", "osd", "script/Latin"}
+	langs := tesseract.Languages{"eng", "deu", "script/Latin", "script/Greek", "~spa"}
+
+	want := []string{"deu", "script/Greek"}
+	if got := langs.Missing(available); !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckLanguages(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng", "osd")
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	if err := client.CheckLanguages("eng+osd"); err != nil {
+		t.Errorf("CheckLanguages(eng+osd) error = %v", err)
+	}
+
+	err := client.CheckLanguages("eng+deu+fra")
+	var langErr *tesseract.LanguageError
+	if !errors.As(err, &langErr) || !errors.Is(err, tesseract.ErrLanguageNotFound) {
+		t.Fatalf("CheckLanguages(eng+deu+fra) error = %v, want *LanguageError", err)
+	}
+	if want := []string{"deu", "fra"}; !reflect.DeepEqual(langErr.Missing, want) {
+		t.Errorf("Missing = %q, want %q", langErr.Missing, want)
+	}
+}
+
+func TestMissingLanguageEntryPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(c *tesseract.Client) error
+	}{
+		{"ImageToString", func(c *tesseract.Client) error {
+			_, err := c.ImageToString(testImage(), "deu")
+			return err
+		}},
+		{"ImageToBoxes", func(c *tesseract.Client) error {
+			_, err := c.ImageToBoxes(testImage(), "deu")
+			return err
+		}},
+		{"ImageToHOCR", func(c *tesseract.Client) error {
+			_, err := c.ImageToHOCR(testImage(), "deu")
+			return err
+		}},
+		{"Recognize", func(c *tesseract.Client) error {
+			_, err := c.Recognize(testImage(), "deu", []tesseract.Format{tesseract.FormatText})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor("5.3.0", "eng")
+			client := newClient(t, tesseract.Config{Executor: exec})
+
+			var langErr *tesseract.LanguageError
+			if err := tt.run(client); !errors.As(err, &langErr) {
+				t.Fatalf("error = %v, want *LanguageError", err)
+			}
+			var listed int
+			for _, call := range exec.Calls() {
+				if reflect.DeepEqual(call.Args, []string{"--list-langs"}) {
+					listed++
+				}
+			}
+			if listed != 1 || len(runs(exec)) != 0 {
+				t.Errorf("--list-langs ran %d times and tesseract %d times, want 1 and 0", listed, len(runs(exec)))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package tesseract_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// fakeBinary is the fake tesseract built from tesseracttest/testdata/fixtures.
+// Tests that depend on its fixtures pass it as Config.TesseractPath.
+var fakeBinary string
+
+// TestMain builds the fake tesseract. It is also put first on PATH, for the
+// tests that use whatever tesseract is installed, when there is none or
+// GOTESSERACT_FAKE=1 asks for it; otherwise those tests run against the
+// real binary.
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "faketesseract")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	fixtures := filepath.Join("tesseracttest", "testdata", "fixtures")
+	if fakeBinary, err = tesseracttest.Build(dir, fixtures); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if _, err := exec.LookPath("tesseract"); err != nil || os.Getenv("GOTESSERACT_FAKE") == "1" {
+		os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+	return m.Run()
+}
+
+// testImage returns a small image with a single white pixel
+func testImage() image.Image {
+	img := image.NewGray(image.Rect(0, 0, 40, 20))
+	img.SetGray(5, 5, color.Gray{Y: 255})
+	return img
+}
+
+// newClient creates a client for cfg, failing the test on error
+func newClient(t testing.TB, cfg tesseract.Config) *tesseract.Client {
+	t.Helper()
+	client, err := tesseract.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+// fixture returns the content of one of fakeBinary's default fixtures
+func fixture(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("tesseracttest", "testdata", "fixtures", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// useFixtures makes fakeBinary answer from a copy of its default fixtures
+// with files added or replaced, for the rest of the test
+func useFixtures(t testing.TB, files map[string]string) {
+	t.Helper()
+	t.Setenv("TESSERACTTEST_FIXTURES", fixtureDir(t, files))
+}
+
+// fixtureDir copies fakeBinary's default fixtures to a temporary directory,
+// adds or replaces files and returns the directory
+func fixtureDir(t testing.TB, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	entries, err := os.ReadDir(filepath.Join("tesseracttest", "testdata", "fixtures"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), fixture(t, e.Name()), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// runs returns the recognition runs exec has seen
+func runs(exec *tesseracttest.Executor) []tesseracttest.Call {
+	var calls []tesseracttest.Call
+	for _, call := range exec.Calls() {
+		if !slices.Contains(call.Args, "--version") && !slices.Contains(call.Args, "--list-langs") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// runArgs returns the arguments of the last recognition run, without the
+// image argument
+func runArgs(exec *tesseracttest.Executor) []string {
+	calls := runs(exec)
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1].Args[1:]
+}
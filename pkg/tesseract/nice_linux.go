@@ -0,0 +1,22 @@
+//go:build linux
+
+package tesseract
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// niceCommand wraps name and args in nice(1) when a non-zero niceness
+// is requested. It fails with ErrInvalidConfig if nice is not installed,
+// so the start failure is not mistaken for a missing tesseract.
+func niceCommand(nice int, name string, args []string) (string, []string, error) {
+	if nice == 0 {
+		return name, args, nil
+	}
+	if _, err := exec.LookPath("nice"); err != nil {
+		return "", nil, fmt.Errorf("%w: nice(1) not found for Config.Nice: %v", ErrInvalidConfig, err)
+	}
+	return "nice", append([]string{"-n", strconv.Itoa(nice), name}, args...), nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package tesseract
+
+// niceCommand returns name and args unchanged; Config.Nice is only
+// honoured on Linux
+func niceCommand(nice int, name string, args []string) (string, []string, error) {
+	return name, args, nil
+}
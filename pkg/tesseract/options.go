@@ -0,0 +1,218 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PageSegMode selects how Tesseract segments the page (--psm).
+// The zero value PSMDefault leaves Tesseract's own default in place, so the
+// constants are offset by one from Tesseract's numeric modes.
+type PageSegMode int
+
+const (
+	// PSMDefault does not pass --psm
+	PSMDefault PageSegMode = iota
+
+	// PSMOSDOnly runs orientation and script detection only (--psm 0)
+	PSMOSDOnly
+
+	// PSMAutoOSD uses automatic segmentation with OSD (--psm 1)
+	PSMAutoOSD
+
+	// PSMAutoOnly uses automatic segmentation without OSD or OCR (--psm 2)
+	PSMAutoOnly
+
+	// PSMAuto uses fully automatic segmentation without OSD (--psm 3)
+	PSMAuto
+
+	// PSMSingleColumn assumes a single column of variable sized text (--psm 4)
+	PSMSingleColumn
+
+	// PSMSingleBlockVertText assumes a single block of vertical text (--psm 5)
+	PSMSingleBlockVertText
+
+	// PSMSingleBlock assumes a single uniform block of text (--psm 6)
+	PSMSingleBlock
+
+	// PSMSingleLine treats the image as a single text line (--psm 7)
+	PSMSingleLine
+
+	// PSMSingleWord treats the image as a single word (--psm 8)
+	PSMSingleWord
+
+	// PSMCircleWord treats the image as a single word in a circle (--psm 9)
+	PSMCircleWord
+
+	// PSMSingleChar treats the image as a single character (--psm 10)
+	PSMSingleChar
+
+	// PSMSparseText finds as much text as possible in no order (--psm 11)
+	PSMSparseText
+
+	// PSMSparseTextOSD is PSMSparseText with OSD (--psm 12)
+	PSMSparseTextOSD
+
+	// PSMRawLine treats the image as a single line, bypassing
+	// Tesseract-specific hacks (--psm 13)
+	PSMRawLine
+)
+
+var pageSegModeNames = [...]string{
+	"default", "osd-only", "auto-osd", "auto-only", "auto", "single-column",
+	"single-block-vert-text", "single-block", "single-line", "single-word",
+	"circle-word", "single-char", "sparse-text", "sparse-text-osd", "raw-line",
+}
+
+// pageSegModeVersions holds the oldest Tesseract supporting each mode
+var pageSegModeVersions = [...]Version{
+	{}, {3, 5, 0}, {3, 5, 0}, {3, 5, 0}, {3, 5, 0},
+	{3, 5, 0}, {3, 5, 0}, {3, 5, 0}, {3, 5, 0}, {3, 5, 0},
+	{3, 5, 0}, {3, 5, 0}, {3, 5, 0}, {3, 5, 0}, {4, 0, 0},
+}
+
+// String returns a readable name for the mode
+func (m PageSegMode) String() string {
+	if m < PSMDefault || m > PSMRawLine {
+		return "PageSegMode(" + strconv.Itoa(int(m)) + ")"
+	}
+	return pageSegModeNames[m]
+}
+
+// EngineMode selects the OCR engine Tesseract uses (--oem).
+// The zero value OEMDefault leaves Tesseract's own default in place, so the
+// constants are offset by one from Tesseract's numeric modes.
+type EngineMode int
+
+const (
+	// OEMDefault does not pass --oem
+	OEMDefault EngineMode = iota
+
+	// OEMTesseractOnly uses the legacy engine only (--oem 0)
+	OEMTesseractOnly
+
+	// OEMLSTMOnly uses the LSTM neural network engine only (--oem 1)
+	OEMLSTMOnly
+
+	// OEMTesseractLSTMCombined uses both engines (--oem 2)
+	OEMTesseractLSTMCombined
+
+	// OEMAuto lets Tesseract pick based on what is available (--oem 3)
+	OEMAuto
+)
+
+var engineModeNames = [...]string{
+	"default", "tesseract-only", "lstm-only", "tesseract-lstm-combined", "auto",
+}
+
+// engineModeVersions holds the oldest Tesseract supporting each mode.
+// Tesseract 3.05 only accepts the legacy engine and its own default.
+var engineModeVersions = [...]Version{
+	{}, {3, 5, 0}, {4, 0, 0}, {4, 0, 0}, {3, 5, 0},
+}
+
+// String returns a readable name for the mode
+func (m EngineMode) String() string {
+	if m < OEMDefault || m > OEMAuto {
+		return "EngineMode(" + strconv.Itoa(int(m)) + ")"
+	}
+	return engineModeNames[m]
+}
+
+// Options adjusts a single OCR call. Zero-valued fields fall back to the
+// client's Config; when several Options are passed, later ones win.
+type Options struct {
+	// PageSegMode overrides Config.PageSegMode
+	PageSegMode PageSegMode
+
+	// EngineMode overrides Config.EngineMode
+	EngineMode EngineMode
+
+	// Variables are merged over Config.Variables, key by key
+	Variables map[string]string
+
+	// Info, if set, is filled with the details of the tesseract run,
+	// including warnings, whether the call succeeds or not
+	Info *RunInfo
+}
+
+// options merges the client's Config defaults with per-call opts
+func (c *Client) options(opts []Options) Options {
+	merged := Options{
+		PageSegMode: c.config.PageSegMode,
+		EngineMode:  c.config.EngineMode,
+		Variables:   c.config.Variables,
+	}
+	for _, o := range opts {
+		if o.PageSegMode != PSMDefault {
+			merged.PageSegMode = o.PageSegMode
+		}
+		if o.EngineMode != OEMDefault {
+			merged.EngineMode = o.EngineMode
+		}
+		merged.Variables = mergeVariables(merged.Variables, o.Variables)
+		if o.Info != nil {
+			merged.Info = o.Info
+		}
+	}
+	return merged
+}
+
+// mergeVariables returns base overlaid with override without modifying
+// either map
+func mergeVariables(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// validateVariable rejects names that tesseract would misparse in a
+// "-c name=value" flag
+func validateVariable(name string) error {
+	if name == "" || strings.ContainsAny(name, "= \t\r\n") {
+		return fmt.Errorf("%w: invalid variable name %q", ErrInvalidConfig, name)
+	}
+	return nil
+}
+
+// flags validates o against the installed Tesseract and returns the
+// matching command line flags
+func (c *Client) flags(o Options) ([]string, error) {
+	var flags []string
+
+	if c.config.TessdataDir != "" {
+		flags = append(flags, "--tessdata-dir", c.config.TessdataDir)
+	}
+
+	if o.PageSegMode < PSMDefault || o.PageSegMode > PSMRawLine {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfig, o.PageSegMode)
+	}
+	if o.PageSegMode != PSMDefault {
+		if err := c.requireVersion("page segmentation mode "+o.PageSegMode.String(), pageSegModeVersions[o.PageSegMode]); err != nil {
+			return nil, err
+		}
+		flags = append(flags, "--psm", strconv.Itoa(int(o.PageSegMode)-1))
+	}
+
+	if o.EngineMode < OEMDefault || o.EngineMode > OEMAuto {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConfig, o.EngineMode)
+	}
+	if o.EngineMode != OEMDefault {
+		if err := c.requireVersion("engine mode "+o.EngineMode.String(), engineModeVersions[o.EngineMode]); err != nil {
+			return nil, err
+		}
+		flags = append(flags, "--oem", strconv.Itoa(int(o.EngineMode)-1))
+	}
+
+	return flags, nil
+}
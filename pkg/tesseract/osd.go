@@ -0,0 +1,116 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// Orientation is the result of Tesseract's orientation and script
+// detection (OSD)
+type Orientation struct {
+	// PageNumber is the zero-based page the result applies to
+	PageNumber int
+
+	// Degrees is the detected orientation of the text, clockwise
+	Degrees int
+
+	// Rotate is the clockwise rotation in degrees that makes the page upright
+	Rotate int
+
+	// OrientationConfidence is Tesseract's confidence in Degrees
+	OrientationConfidence float64
+
+	// Script is the detected script name, e.g. "Latin" or "Cyrillic"
+	Script string
+
+	// ScriptConfidence is Tesseract's confidence in Script
+	ScriptConfidence float64
+}
+
+// DetectOrientation runs orientation and script detection (--psm 0) on img
+func (c *Client) DetectOrientation(img image.Image, opts ...Options) (*Orientation, error) {
+	return c.DetectOrientationContext(context.Background(), img, opts...)
+}
+
+// DetectOrientationContext is like DetectOrientation but stops the
+// tesseract process when ctx is cancelled or its deadline expires
+func (c *Client) DetectOrientationContext(ctx context.Context, img image.Image, opts ...Options) (*Orientation, error) {
+	if err := validateImageFormat(img); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest("stdout", "", append(opts[:len(opts):len(opts)], Options{PageSegMode: PSMOSDOnly}))
+	if err != nil {
+		return nil, err
+	}
+	// OSD only needs osd.traineddata; a recognition language would just
+	// add another model that has to be installed and loaded
+	req.lang = ""
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	if c.config.Installer != nil {
+		if err := c.validateLanguage(ctx, "osd"); err != nil {
+			return nil, err
+		}
+	}
+
+	// A missing osd.traineddata fails with an OCRError matching
+	// ErrOSDNotInstalled
+	res, err := c.runOCR(ctx, ImageInput(img), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOSD(string(res.Stdout))
+}
+
+func parseOSD(data string) (*Orientation, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, ErrEmptyOutput
+	}
+
+	var o Orientation
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "Page number":
+			o.PageNumber, err = strconv.Atoi(value)
+		case "Orientation in degrees":
+			o.Degrees, err = strconv.Atoi(value)
+		case "Rotate":
+			o.Rotate, err = strconv.Atoi(value)
+		case "Orientation confidence":
+			o.OrientationConfidence, err = strconv.ParseFloat(value, 64)
+		case "Script":
+			o.Script = value
+		case "Script confidence":
+			o.ScriptConfidence, err = strconv.ParseFloat(value, 64)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad OSD %s %q", ErrInvalidOutput, key, value)
+		}
+		seen[key] = true
+	}
+
+	for _, key := range []string{"Orientation in degrees", "Rotate", "Script"} {
+		if !seen[key] {
+			return nil, fmt.Errorf("%w: OSD output missing %q", ErrInvalidOutput, key)
+		}
+	}
+	return &o, nil
+}
@@ -0,0 +1,51 @@
+package tesseract
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const sampleOSD = `Page number: 0
+Orientation in degrees: 270
+Rotate: 90
+Orientation confidence: 21.27
+Script: Latin
+Script confidence: 4.14
+`
+
+func TestParseOSD(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Orientation
+		wantErr error
+	}{
+		{
+			name: "Valid",
+			data: sampleOSD,
+			want: &Orientation{
+				Degrees:               270,
+				Rotate:                90,
+				OrientationConfidence: 21.27,
+				Script:                "Latin",
+				ScriptConfidence:      4.14,
+			},
+		},
+		{"Empty", "", nil, ErrEmptyOutput},
+		{"Missing script", "Orientation in degrees: 0\nRotate: 0\n", nil, ErrInvalidOutput},
+		{"Bad number", "Orientation in degrees: up\nRotate: 0\nScript: Latin\n", nil, ErrInvalidOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOSD(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseOSD() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOSD() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
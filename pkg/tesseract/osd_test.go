@@ -0,0 +1,52 @@
+package tesseract_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestDetectOrientation(t *testing.T) {
+	tests := []struct {
+		name    string
+		exec    tesseract.Execution
+		wantErr error
+	}{
+		{
+			name: "Detected",
+			exec: tesseract.Execution{Stdout: fixture(t, "osd.txt")},
+		},
+		{
+			name: "Missing OSD data",
+			exec: tesseract.Execution{
+				ExitCode: 1,
+				Stderr: []byte("Error opening data file /usr/share/tessdata/osd.traineddata\n" +
+					"Failed loading language 'osd'\n"),
+			},
+			wantErr: tesseract.ErrOSDNotInstalled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor("5.3.0", "eng", "osd")
+			exec.Respond(tt.exec, "--psm", "0")
+			client := newClient(t, tesseract.Config{Executor: exec, Language: "eng"})
+
+			o, err := client.DetectOrientation(testImage(), tesseract.Options{PageSegMode: tesseract.PSMSingleBlock})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectOrientation() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (o.Script != "Latin" || o.OrientationConfidence != 12.5) {
+				t.Errorf("DetectOrientation() = %+v", o)
+			}
+			// OSD runs without a recognition language
+			if got, want := runArgs(exec), []string{"stdout", "--psm", "0"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("command line = %q, want %q", got, want)
+			}
+		})
+	}
+}
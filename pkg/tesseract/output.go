@@ -1,6 +1,9 @@
 package tesseract
 
 import (
+	"fmt"
+	"image"
+	"strconv"
 	"strings"
 )
 
@@ -26,3 +29,60 @@ func parseTSV(data string) map[string][]string {
 	}
 	return result
 }
+
+// tsvColumns is the header tesseract writes at the top of TSV output
+var tsvColumns = []string{
+	"level", "page_num", "block_num", "par_num", "line_num", "word_num",
+	"left", "top", "width", "height", "conf", "text",
+}
+
+func parseWords(data string) ([]Word, error) {
+	rows := strings.Split(strings.TrimRight(data, "\r\n"), "\n")
+	header := strings.Split(strings.TrimRight(rows[0], "\r"), "\t")
+	for i, name := range tsvColumns {
+		if i >= len(header) || header[i] != name {
+			return nil, fmt.Errorf("%w: unexpected TSV header %q", ErrInvalidOutput, rows[0])
+		}
+	}
+
+	words := make([]Word, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		row = strings.TrimRight(row, "\r")
+		if row == "" {
+			continue
+		}
+		cols := strings.SplitN(row, "\t", len(tsvColumns))
+		if len(cols) < len(tsvColumns)-1 {
+			return nil, fmt.Errorf("%w: TSV row %d has %d columns", ErrInvalidOutput, i+2, len(cols))
+		}
+
+		var nums [10]int
+		for j := range nums {
+			n, err := strconv.Atoi(cols[j])
+			if err != nil {
+				return nil, fmt.Errorf("%w: TSV row %d: bad %s %q", ErrInvalidOutput, i+2, tsvColumns[j], cols[j])
+			}
+			nums[j] = n
+		}
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: TSV row %d: bad conf %q", ErrInvalidOutput, i+2, cols[10])
+		}
+
+		w := Word{
+			Level:    Level(nums[0]),
+			PageNum:  nums[1],
+			BlockNum: nums[2],
+			ParNum:   nums[3],
+			LineNum:  nums[4],
+			WordNum:  nums[5],
+			Bounds:   image.Rect(nums[6], nums[7], nums[6]+nums[8], nums[7]+nums[9]),
+			Conf:     conf,
+		}
+		if len(cols) == len(tsvColumns) {
+			w.Text = cols[11]
+		}
+		words = append(words, w)
+	}
+	return words, nil
+}
@@ -0,0 +1,146 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PageResult holds the outputs for one page of a multi-page run. Only the
+// fields of the requested formats are set.
+type PageResult struct {
+	// Number is the page number, counting from 1 across all inputs
+	Number int
+
+	// Text is the FormatText output of the page, without the trailing
+	// form feed tesseract separates pages with
+	Text string
+
+	// Words are the FormatTSV records of the page
+	Words []Word
+
+	// Boxes are the FormatBox or FormatLSTMBox records of the page. Their
+	// Page field keeps tesseract's zero-based numbering, Number-1.
+	Boxes []Box
+}
+
+// pageFormats are the formats RecognizePages can split into pages
+var pageFormats = map[Format]bool{
+	FormatText: true, FormatTSV: true, FormatBox: true, FormatLSTMBox: true,
+}
+
+// RecognizePages performs OCR on a multi-page document in a single
+// tesseract run and returns the requested formats split per page, keyed by
+// page number. Each input may itself be a multi-page TIFF; several inputs
+// are passed to tesseract as an image list, their pages numbered in order.
+// Only FormatText, FormatTSV, FormatBox and FormatLSTMBox can be requested.
+// Tesseract 3.x only separates the pages of FormatText when
+// include_page_breaks is set, so it is set for those versions.
+func (c *Client) RecognizePages(inputs []Input, lang string, requested []Format, opts ...Options) (map[int]*PageResult, error) {
+	return c.RecognizePagesContext(context.Background(), inputs, lang, requested, opts...)
+}
+
+// RecognizePagesContext is like RecognizePages but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) RecognizePagesContext(ctx context.Context, inputs []Input, lang string, requested []Format, opts ...Options) (map[int]*PageResult, error) {
+	in := ListInput(inputs...)
+	if len(inputs) == 1 {
+		in = inputs[0]
+	}
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	selected, err := selectFormats(requested)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range selected {
+		if !pageFormats[f] {
+			return nil, fmt.Errorf("%w: %s output cannot be split into pages", ErrInvalidConfig, f)
+		}
+		if err := c.requireVersion(string(f)+" output", formatOutputs[f].version); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req, err := c.newRequest("", lang, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range selected {
+		if err := req.requireFormat(f); err != nil {
+			return nil, err
+		}
+		if f == FormatText {
+			if err := c.requirePageBreaks(&req); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res, err := c.runOCR(ctx, in, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := readResult(res.Files, selected)
+	if err != nil {
+		return nil, err
+	}
+	return splitPages(result, selected), nil
+}
+
+// splitPages distributes the outputs in res over pages
+func splitPages(res *Result, selected []Format) map[int]*PageResult {
+	pages := make(map[int]*PageResult)
+	page := func(n int) *PageResult {
+		p, ok := pages[n]
+		if !ok {
+			p = &PageResult{Number: n}
+			pages[n] = p
+		}
+		return p
+	}
+
+	for _, f := range selected {
+		switch f {
+		case FormatText:
+			// Tesseract ends every page with a form feed
+			texts := strings.Split(res.Text, "\f")
+			if len(texts) > 1 && strings.TrimSpace(texts[len(texts)-1]) == "" {
+				texts = texts[:len(texts)-1]
+			}
+			for i, text := range texts {
+				page(i + 1).Text = text
+			}
+		case FormatTSV:
+			for _, w := range res.Words {
+				p := page(w.PageNum)
+				p.Words = append(p.Words, w)
+			}
+		case FormatBox, FormatLSTMBox:
+			for _, b := range res.Boxes {
+				p := page(b.Page + 1)
+				p.Boxes = append(p.Boxes, b)
+			}
+		}
+	}
+	return pages
+}
+
+// PageNumbers returns the keys of pages in ascending order
+func PageNumbers(pages map[int]*PageResult) []int {
+	numbers := make([]int, 0, len(pages))
+	for n := range pages {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
@@ -0,0 +1,183 @@
+package tesseract_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// pageRenderer returns a response that renders one page per line of an
+// image list, or two pages for a single image, standing in for a TIFF. The
+// image list of the last run is stored in list.
+func pageRenderer(list *[]string) func(tesseracttest.Call) tesseract.Execution {
+	return func(call tesseracttest.Call) tesseract.Execution {
+		n := 2
+		if strings.HasSuffix(call.Args[0], ".txt") {
+			data, err := os.ReadFile(call.Args[0])
+			if err != nil {
+				return tesseract.Execution{ExitCode: 1, Stderr: []byte(err.Error())}
+			}
+			*list = strings.Fields(string(data))
+			n = len(*list)
+		}
+
+		var txt, tsv, box strings.Builder
+		tsv.WriteString("level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n")
+		for p := 1; p <= n; p++ {
+			fmt.Fprintf(&txt, "page %d\n\f", p)
+			fmt.Fprintf(&tsv, "1\t%d\t0\t0\t0\t0\t0\t0\t100\t100\t-1\t\n5\t%d\t1\t1\t1\t1\t10\t10\t40\t20\t90\tpage\n", p, p)
+			fmt.Fprintf(&box, "p 10 10 20 20 %d\n", p-1)
+		}
+
+		files := make(map[string][]byte)
+		for _, arg := range call.Args[2:] {
+			switch arg {
+			case "txt":
+				files["txt"] = []byte(txt.String())
+			case "tsv":
+				files["tsv"] = []byte(tsv.String())
+			case "makebox":
+				files["box"] = []byte(box.String())
+			}
+		}
+		return tesseract.Execution{Files: files}
+	}
+}
+
+func TestRecognizePages(t *testing.T) {
+	var list []string
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(pageRenderer(&list))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name      string
+		inputs    []tesseract.Input
+		wantPages int
+		wantList  []string
+	}{
+		{
+			name:      "Multi-page TIFF",
+			inputs:    []tesseract.Input{tesseract.BytesInput([]byte("II*\x00\x08\x00\x00\x00"), "")},
+			wantPages: 2,
+		},
+		{
+			name: "Image list",
+			inputs: []tesseract.Input{
+				tesseract.ImageInput(testImage()),
+				tesseract.BytesInput(jpegData(t), ""),
+				tesseract.ImageInput(testImage()),
+			},
+			wantPages: 3,
+			wantList:  []string{".png", ".jpeg", ".png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list = nil
+			pages, err := client.RecognizePages(tt.inputs, "eng", []tesseract.Format{
+				tesseract.FormatText, tesseract.FormatTSV, tesseract.FormatBox,
+			})
+			if err != nil {
+				t.Fatalf("RecognizePages() error = %v", err)
+			}
+			if got := tesseract.PageNumbers(pages); len(got) != tt.wantPages {
+				t.Fatalf("RecognizePages() pages = %v, want %d", got, tt.wantPages)
+			}
+			for n, page := range pages {
+				if page.Number != n {
+					t.Errorf("page %d has Number %d", n, page.Number)
+				}
+				if want := fmt.Sprintf("page %d\n", n); page.Text != want {
+					t.Errorf("page %d text = %q, want %q", n, page.Text, want)
+				}
+				if len(page.Words) != 2 || page.Words[1].PageNum != n {
+					t.Errorf("page %d words = %+v", n, page.Words)
+				}
+				if len(page.Boxes) != 1 || page.Boxes[0].Page != n-1 {
+					t.Errorf("page %d boxes = %+v", n, page.Boxes)
+				}
+			}
+
+			var exts []string
+			for _, l := range list {
+				exts = append(exts, filepath.Ext(l))
+			}
+			if !reflect.DeepEqual(exts, tt.wantList) {
+				t.Errorf("image list extensions = %q, want %q", exts, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestRecognizePagesPageBreaks(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"3.05.02", true},
+		{"4.1.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			var list []string
+			exec := tesseracttest.NewExecutor(tt.version, "eng")
+			exec.RespondFunc(pageRenderer(&list))
+			client := newClient(t, tesseract.Config{Executor: exec})
+
+			inputs := []tesseract.Input{tesseract.ImageInput(testImage()), tesseract.ImageInput(testImage())}
+			pages, err := client.RecognizePages(inputs, "eng", []tesseract.Format{tesseract.FormatText})
+			if err != nil || len(pages) != 2 {
+				t.Fatalf("RecognizePages() = %d pages, %v; want 2", len(pages), err)
+			}
+			// Tesseract 3 only separates pages when asked to
+			if got := strings.Contains(strings.Join(runArgs(exec), " "), "include_page_breaks=1"); got != tt.want {
+				t.Errorf("command line %q passes include_page_breaks=1: %v, want %v", runArgs(exec), got, tt.want)
+			}
+
+			_, err = client.RecognizePages(inputs, "eng", []tesseract.Format{tesseract.FormatText},
+				tesseract.Options{Variables: map[string]string{"include_page_breaks": "0"}})
+			if tt.want && !errors.Is(err, tesseract.ErrVariableConflict) {
+				t.Errorf("RecognizePages() with include_page_breaks=0 error = %v, want %v", err, tesseract.ErrVariableConflict)
+			}
+		})
+	}
+}
+
+func TestRecognizePagesVariableConflict(t *testing.T) {
+	var list []string
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(pageRenderer(&list))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	inputs := []tesseract.Input{tesseract.ImageInput(testImage()), tesseract.ImageInput(testImage())}
+	opts := tesseract.Options{Variables: map[string]string{"tessedit_create_tsv": "0"}}
+	_, err := client.RecognizePages(inputs, "eng", []tesseract.Format{tesseract.FormatTSV}, opts)
+	if !errors.Is(err, tesseract.ErrVariableConflict) {
+		t.Errorf("RecognizePages() with tessedit_create_tsv=0 error = %v, want %v", err, tesseract.ErrVariableConflict)
+	}
+}
+
+func TestRecognizePagesInvalid(t *testing.T) {
+	var list []string
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(pageRenderer(&list))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	if _, err := client.RecognizePages(nil, "eng", []tesseract.Format{tesseract.FormatText}); !errors.Is(err, tesseract.ErrInvalidImage) {
+		t.Errorf("RecognizePages() with no inputs error = %v, want %v", err, tesseract.ErrInvalidImage)
+	}
+	_, err := client.RecognizePages([]tesseract.Input{tesseract.ImageInput(testImage())}, "eng", []tesseract.Format{tesseract.FormatPDF})
+	if !errors.Is(err, tesseract.ErrInvalidConfig) {
+		t.Errorf("RecognizePages() with PDF error = %v, want %v", err, tesseract.ErrInvalidConfig)
+	}
+}
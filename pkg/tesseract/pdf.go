@@ -0,0 +1,116 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// PDFOptions controls searchable PDF output
+type PDFOptions struct {
+	// TextOnly produces an invisible text layer without the page image,
+	// for overlaying on the original document (textonly_pdf)
+	TextOnly bool
+
+	// JPEGQuality sets the quality (1-100) used when the page image is
+	// embedded as JPEG (jpg_quality, Tesseract 4.0 or newer); zero keeps
+	// Tesseract's default
+	JPEGQuality int
+
+	// Title sets the PDF title metadata (document_title, Tesseract 4.0 or
+	// newer)
+	Title string
+}
+
+// pdfVariableVersions lists the first tesseract release that reads each
+// variable PDFOptions sets
+var pdfVariableVersions = map[string]Version{
+	"textonly_pdf":   {3, 5, 0},
+	"jpg_quality":    {4, 0, 0},
+	"document_title": {4, 0, 0},
+}
+
+// variables returns the Tesseract variables that implement o
+func (o PDFOptions) variables() (map[string]string, error) {
+	vars := make(map[string]string)
+	if o.TextOnly {
+		vars["textonly_pdf"] = "1"
+	}
+	if o.JPEGQuality != 0 {
+		if o.JPEGQuality < 1 || o.JPEGQuality > 100 {
+			return nil, fmt.Errorf("%w: JPEG quality %d out of range 1-100", ErrInvalidConfig, o.JPEGQuality)
+		}
+		vars["jpg_quality"] = strconv.Itoa(o.JPEGQuality)
+	}
+	if o.Title != "" {
+		vars["document_title"] = o.Title
+	}
+	return vars, nil
+}
+
+// ImageToPDF performs OCR and returns a searchable PDF
+func (c *Client) ImageToPDF(img image.Image, lang string, pdf PDFOptions, opts ...Options) ([]byte, error) {
+	return c.ImageToPDFContext(context.Background(), img, lang, pdf, opts...)
+}
+
+// ImageToPDFContext is like ImageToPDF but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) ImageToPDFContext(ctx context.Context, img image.Image, lang string, pdf PDFOptions, opts ...Options) ([]byte, error) {
+	return c.InputToPDFContext(ctx, ImageInput(img), lang, pdf, opts...)
+}
+
+// InputToPDF performs OCR on in and returns a searchable PDF. Pass a
+// ListInput to combine several pages into one document.
+func (c *Client) InputToPDF(in Input, lang string, pdf PDFOptions, opts ...Options) ([]byte, error) {
+	return c.InputToPDFContext(context.Background(), in, lang, pdf, opts...)
+}
+
+// InputToPDFContext is like InputToPDF but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) InputToPDFContext(ctx context.Context, in Input, lang string, pdf PDFOptions, opts ...Options) ([]byte, error) {
+	vars, err := c.pdfVariables(pdf)
+	if err != nil {
+		return nil, err
+	}
+	return c.extension(ctx, in, lang, "pdf", opts, vars)
+}
+
+// pdfVariables returns the Tesseract variables that implement o, failing
+// with a FeatureError if the installed tesseract does not read one
+func (c *Client) pdfVariables(o PDFOptions) (map[string]string, error) {
+	vars, err := o.variables()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := c.requireVersion(name+" PDF option", pdfVariableVersions[name]); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// WritePDF performs OCR and writes a searchable PDF to w
+func (c *Client) WritePDF(w io.Writer, img image.Image, lang string, pdf PDFOptions, opts ...Options) error {
+	return c.WritePDFContext(context.Background(), w, img, lang, pdf, opts...)
+}
+
+// WritePDFContext is like WritePDF but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) WritePDFContext(ctx context.Context, w io.Writer, img image.Image, lang string, pdf PDFOptions, opts ...Options) error {
+	out, err := c.ImageToPDFContext(ctx, img, lang, pdf, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,100 @@
+package tesseract_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestImageToPDF(t *testing.T) {
+	pdfData := []byte("%PDF-1.5\n\x00\xff\n%%EOF")
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"pdf": pdfData}})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	pdf := tesseract.PDFOptions{TextOnly: true, JPEGQuality: 60, Title: "Receipt 42"}
+	var buf bytes.Buffer
+	if err := client.WritePDF(&buf, testImage(), "eng", pdf); err != nil {
+		t.Fatalf("WritePDF() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), pdfData) {
+		t.Errorf("WritePDF() wrote %q, want %q", buf.Bytes(), pdfData)
+	}
+
+	want := []string{
+		"-l", "eng",
+		"-c", "document_title=Receipt 42",
+		"-c", "jpg_quality=60",
+		"-c", "tessedit_create_pdf=1",
+		"-c", "textonly_pdf=1",
+	}
+	if args := runArgs(exec)[1:]; !reflect.DeepEqual(args, want) {
+		t.Errorf("command line = %q, want %q", args, want)
+	}
+}
+
+func TestImageToPDFInvalid(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"pdf": []byte("pdf\n")}})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name    string
+		pdf     tesseract.PDFOptions
+		opts    tesseract.Options
+		wantErr error
+	}{
+		{"JPEG quality", tesseract.PDFOptions{JPEGQuality: 101}, tesseract.Options{}, tesseract.ErrInvalidConfig},
+		{
+			"Conflicting text only",
+			tesseract.PDFOptions{TextOnly: true},
+			tesseract.Options{Variables: map[string]string{"textonly_pdf": "0"}},
+			tesseract.ErrVariableConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ImageToPDF(testImage(), "", tt.pdf, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ImageToPDF() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageToPDFVersion(t *testing.T) {
+	exec := tesseracttest.NewExecutor("3.05.02", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"pdf": []byte("pdf\n")}})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name    string
+		pdf     tesseract.PDFOptions
+		wantErr bool
+	}{
+		{"Text only", tesseract.PDFOptions{TextOnly: true}, false},
+		{"JPEG quality", tesseract.PDFOptions{JPEGQuality: 60}, true},
+		{"Title", tesseract.PDFOptions{Title: "Receipt 42"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ImageToPDF(testImage(), "eng", tt.pdf)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("ImageToPDF() error = %v", err)
+				}
+				return
+			}
+			var featureErr *tesseract.FeatureError
+			if !errors.As(err, &featureErr) || !errors.Is(err, tesseract.ErrFeatureUnsupported) {
+				t.Errorf("ImageToPDF() error = %v, want *FeatureError", err)
+			}
+		})
+	}
+}
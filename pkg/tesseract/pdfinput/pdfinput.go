@@ -0,0 +1,125 @@
+// Package pdfinput runs OCR on PDF documents by rasterising their pages
+// with a locally installed pdftoppm or mutool and passing the images to a
+// tesseract.Client
+package pdfinput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+)
+
+// DefaultDPI is the resolution pages are rendered at when Config.DPI is zero
+const DefaultDPI = 300
+
+var (
+	// ErrNoRasterizer indicates neither pdftoppm nor mutool is installed
+	ErrNoRasterizer = errors.New("pdfinput: no PDF rasterizer found (install pdftoppm or mutool)")
+
+	// ErrNoPages indicates the rasteriser rendered no pages
+	ErrNoPages = errors.New("pdfinput: PDF has no pages")
+)
+
+// Config holds the options of a Converter
+type Config struct {
+	// Rasterizer renders the pages; nil uses pdftoppm, or mutool if
+	// pdftoppm is not installed
+	Rasterizer Rasterizer
+
+	// DPI is the rendering resolution; zero means DefaultDPI
+	DPI int
+}
+
+// Converter rasterises PDFs and recognises their pages with a Client
+type Converter struct {
+	client *tesseract.Client
+	config Config
+}
+
+// NewConverter creates a Converter using client for OCR. It fails with
+// ErrNoRasterizer if cfg.Rasterizer is nil and no rasteriser is installed,
+// and with tesseract.ErrInvalidConfig if cfg.DPI is negative.
+func NewConverter(client *tesseract.Client, cfg Config) (*Converter, error) {
+	if cfg.DPI < 0 {
+		return nil, fmt.Errorf("%w: pdfinput: negative DPI %d", tesseract.ErrInvalidConfig, cfg.DPI)
+	}
+	if cfg.Rasterizer == nil {
+		r, err := findRasterizer()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Rasterizer = r
+	}
+	if cfg.DPI == 0 {
+		cfg.DPI = DefaultDPI
+	}
+	return &Converter{client: client, config: cfg}, nil
+}
+
+// Pages recognises every page of the PDF at pdfPath in one tesseract run
+// and returns the requested formats per page, see Client.RecognizePages
+func (c *Converter) Pages(ctx context.Context, pdfPath, lang string, requested []tesseract.Format, opts ...tesseract.Options) (map[int]*tesseract.PageResult, error) {
+	var pages map[int]*tesseract.PageResult
+	err := c.rasterize(ctx, pdfPath, func(inputs []tesseract.Input) error {
+		var err error
+		pages, err = c.client.RecognizePagesContext(ctx, inputs, lang, requested, opts...)
+		return err
+	})
+	return pages, err
+}
+
+// Text returns the recognised text of every page of the PDF at pdfPath,
+// in page order
+func (c *Converter) Text(ctx context.Context, pdfPath, lang string, opts ...tesseract.Options) ([]string, error) {
+	pages, err := c.Pages(ctx, pdfPath, lang, []tesseract.Format{tesseract.FormatText}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	texts := make([]string, 0, len(pages))
+	for _, n := range tesseract.PageNumbers(pages) {
+		texts = append(texts, pages[n].Text)
+	}
+	return texts, nil
+}
+
+// WriteSearchablePDF recognises the PDF at pdfPath and writes a searchable
+// PDF with all its pages to w
+func (c *Converter) WriteSearchablePDF(ctx context.Context, w io.Writer, pdfPath, lang string, pdf tesseract.PDFOptions, opts ...tesseract.Options) error {
+	return c.rasterize(ctx, pdfPath, func(inputs []tesseract.Input) error {
+		out, err := c.client.InputToPDFContext(ctx, tesseract.ListInput(inputs...), lang, pdf, opts...)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	})
+}
+
+// rasterize renders the pages of pdfPath into a temporary directory and
+// calls fn with them
+func (c *Converter) rasterize(ctx context.Context, pdfPath string, fn func([]tesseract.Input) error) error {
+	if _, err := os.Stat(pdfPath); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "pdfinput_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	paths, err := c.config.Rasterizer.Rasterize(ctx, pdfPath, dir, c.config.DPI)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]tesseract.Input, len(paths))
+	for i, path := range paths {
+		inputs[i] = tesseract.FileInput(path)
+	}
+	return fn(inputs)
+}
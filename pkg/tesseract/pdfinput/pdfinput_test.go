@@ -0,0 +1,224 @@
+package pdfinput_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/pdfinput"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// fakeRasterizer writes a shell script that logs its arguments to
+// $0.args and runs body
+func fakeRasterizer(t *testing.T, name, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake rasterizer script requires a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), name)
+	script := "#!/bin/sh\necho \"$@\" > \"$0.args\"\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// png writes a PNG signature to the file named by its argument
+const png = `png() { printf '\211PNG\r\n\032\n' > "$1"; }
+`
+
+func writePDF(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scan.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.5\n%%EOF\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRasterizers(t *testing.T) {
+	pdf := writePDF(t)
+
+	pdftoppm := fakeRasterizer(t, "pdftoppm", png+`for i in 01 02 10; do png "$5-$i.png"; done`)
+	mutool := fakeRasterizer(t, "mutool", png+`for i in 1 2 10; do png "$(printf "$6" $i)"; done`)
+
+	tests := []struct {
+		name     string
+		r        pdfinput.Rasterizer
+		path     string
+		wantArgs string
+		wantBase []string
+	}{
+		{
+			name:     "pdftoppm",
+			r:        &pdfinput.Pdftoppm{Path: pdftoppm},
+			path:     pdftoppm,
+			wantArgs: "-r 150 -png " + pdf + " DIR/page",
+			wantBase: []string{"page-01.png", "page-02.png", "page-10.png"},
+		},
+		{
+			name:     "mutool",
+			r:        &pdfinput.Mutool{Path: mutool},
+			path:     mutool,
+			wantArgs: "draw -q -r 150 -o DIR/page-%d.png " + pdf,
+			wantBase: []string{"page-1.png", "page-2.png", "page-10.png"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			paths, err := tt.r.Rasterize(context.Background(), pdf, dir, 150)
+			if err != nil {
+				t.Fatalf("Rasterize() error = %v", err)
+			}
+
+			var base []string
+			for _, p := range paths {
+				base = append(base, filepath.Base(p))
+			}
+			if !reflect.DeepEqual(base, tt.wantBase) {
+				t.Errorf("Rasterize() = %q, want %q", base, tt.wantBase)
+			}
+
+			args, _ := os.ReadFile(tt.path + ".args")
+			got := strings.ReplaceAll(strings.TrimSpace(string(args)), dir, "DIR")
+			if got != tt.wantArgs {
+				t.Errorf("arguments = %q, want %q", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRasterizersDashPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "-x.pdf"), []byte("%PDF-1.5\n%%EOF\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	// dir may be reached through a symlink, e.g. /tmp on macOS
+	if dir, err = os.Getwd(); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "-x.pdf")
+
+	pdftoppm := fakeRasterizer(t, "pdftoppm", png+`png "$5-1.png"`)
+	mutool := fakeRasterizer(t, "mutool", png+`png "$(printf "$6" 1)"`)
+	for _, tt := range []struct {
+		r    pdfinput.Rasterizer
+		path string
+	}{
+		{&pdfinput.Pdftoppm{Path: pdftoppm}, pdftoppm},
+		{&pdfinput.Mutool{Path: mutool}, mutool},
+	} {
+		if _, err := tt.r.Rasterize(context.Background(), "-x.pdf", t.TempDir(), 150); err != nil {
+			t.Fatalf("%s Rasterize(-x.pdf) error = %v", filepath.Base(tt.path), err)
+		}
+		args, _ := os.ReadFile(tt.path + ".args")
+		if fields := strings.Fields(string(args)); !slices.Contains(fields, want) || slices.Contains(fields, "-x.pdf") {
+			t.Errorf("%s arguments = %q, want the PDF as %q", filepath.Base(tt.path), args, want)
+		}
+	}
+}
+
+func TestConverter(t *testing.T) {
+	pdf := writePDF(t)
+	rasterizer := &pdfinput.Pdftoppm{
+		Path: fakeRasterizer(t, "pdftoppm", png+`png "$5-1.png"; png "$5-2.png"`),
+	}
+
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"pdf": []byte("%PDF-1.5 both pages")}}, "tessedit_create_pdf=1")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"txt": []byte("first\n\fsecond\n\f")}}, "txt")
+	client, err := tesseract.NewClient(tesseract.Config{Executor: exec})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	conv, err := pdfinput.NewConverter(client, pdfinput.Config{Rasterizer: rasterizer})
+	if err != nil {
+		t.Fatalf("NewConverter() error = %v", err)
+	}
+
+	texts, err := conv.Text(context.Background(), pdf, "eng")
+	if want := []string{"first\n", "second\n"}; err != nil || !reflect.DeepEqual(texts, want) {
+		t.Errorf("Text() = %q, %v; want %q", texts, err, want)
+	}
+	args, _ := os.ReadFile(rasterizer.Path + ".args")
+	if !strings.HasPrefix(string(args), "-r 300 ") {
+		t.Errorf("rasterizer arguments = %q, want default DPI 300", args)
+	}
+
+	var buf bytes.Buffer
+	if err := conv.WriteSearchablePDF(context.Background(), &buf, pdf, "eng", tesseract.PDFOptions{}); err != nil {
+		t.Fatalf("WriteSearchablePDF() error = %v", err)
+	}
+	if buf.String() != "%PDF-1.5 both pages" {
+		t.Errorf("WriteSearchablePDF() wrote %q", buf.String())
+	}
+
+	// Both pages go to tesseract in a single run through an image list
+	calls := exec.Calls()
+	if image := calls[len(calls)-1].Args[0]; filepath.Ext(image) != ".txt" {
+		t.Errorf("tesseract image argument = %q, want an image list", image)
+	}
+}
+
+func TestConverterErrors(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	client, err := tesseract.NewClient(tesseract.Config{Executor: exec})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	empty := &pdfinput.Mutool{Path: fakeRasterizer(t, "mutool", "exit 0")}
+	conv, err := pdfinput.NewConverter(client, pdfinput.Config{Rasterizer: empty})
+	if err != nil {
+		t.Fatalf("NewConverter() error = %v", err)
+	}
+	if _, err := conv.Text(context.Background(), writePDF(t), "eng"); !errors.Is(err, pdfinput.ErrNoPages) {
+		t.Errorf("Text() error = %v, want %v", err, pdfinput.ErrNoPages)
+	}
+
+	broken := &pdfinput.Mutool{Path: fakeRasterizer(t, "mutool", "echo 'cannot open document' >&2; exit 1")}
+	conv, _ = pdfinput.NewConverter(client, pdfinput.Config{Rasterizer: broken})
+	_, err = conv.Text(context.Background(), writePDF(t), "eng")
+	if err == nil || !strings.Contains(err.Error(), "cannot open document") {
+		t.Errorf("Text() error = %v, want rasterizer stderr", err)
+	}
+
+	if _, err := pdfinput.NewConverter(client, pdfinput.Config{Rasterizer: empty, DPI: -300}); !errors.Is(err, tesseract.ErrInvalidConfig) {
+		t.Errorf("NewConverter() with negative DPI error = %v, want %v", err, tesseract.ErrInvalidConfig)
+	}
+
+	// A rasteriser outliving the context's deadline is a timeout
+	slow := &pdfinput.Mutool{Path: fakeRasterizer(t, "mutool", "exec sleep 10")}
+	conv, _ = pdfinput.NewConverter(client, pdfinput.Config{Rasterizer: slow})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := conv.Text(ctx, writePDF(t), "eng"); !tesseract.IsTimeout(err) {
+		t.Errorf("Text() with an expired deadline error = %v, want a timeout", err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := pdfinput.NewConverter(client, pdfinput.Config{}); !errors.Is(err, pdfinput.ErrNoRasterizer) {
+		t.Errorf("NewConverter() error = %v, want %v", err, pdfinput.ErrNoRasterizer)
+	}
+}
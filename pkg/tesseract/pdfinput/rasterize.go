@@ -0,0 +1,134 @@
+package pdfinput
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+)
+
+// Rasterizer renders the pages of a PDF to image files
+type Rasterizer interface {
+	// Rasterize renders every page of the PDF at pdfPath into dir at dpi
+	// and returns the image paths in page order
+	Rasterize(ctx context.Context, pdfPath, dir string, dpi int) ([]string, error)
+}
+
+// Pdftoppm rasterises with poppler's pdftoppm
+type Pdftoppm struct {
+	// Path is the pdftoppm binary; empty looks it up in PATH
+	Path string
+}
+
+// Rasterize implements Rasterizer
+func (p *Pdftoppm) Rasterize(ctx context.Context, pdfPath, dir string, dpi int) ([]string, error) {
+	pdfPath, err := absPath(pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	// pdftoppm appends "-N.png", zero-padded to the page count
+	prefix := filepath.Join(dir, "page")
+	err = run(ctx, command(p.Path, "pdftoppm"), "-r", strconv.Itoa(dpi), "-png", pdfPath, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return pageFiles(dir)
+}
+
+// Mutool rasterises with MuPDF's mutool draw
+type Mutool struct {
+	// Path is the mutool binary; empty looks it up in PATH
+	Path string
+}
+
+// Rasterize implements Rasterizer
+func (m *Mutool) Rasterize(ctx context.Context, pdfPath, dir string, dpi int) ([]string, error) {
+	pdfPath, err := absPath(pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	out := filepath.Join(dir, "page-%d.png")
+	err = run(ctx, command(m.Path, "mutool"), "draw", "-q", "-r", strconv.Itoa(dpi), "-o", out, pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	return pageFiles(dir)
+}
+
+// findRasterizer returns the first rasteriser installed in PATH
+func findRasterizer() (Rasterizer, error) {
+	if path, err := exec.LookPath("pdftoppm"); err == nil {
+		return &Pdftoppm{Path: path}, nil
+	}
+	if path, err := exec.LookPath("mutool"); err == nil {
+		return &Mutool{Path: path}, nil
+	}
+	return nil, ErrNoRasterizer
+}
+
+// absPath makes path absolute so a relative name starting with "-" is not
+// parsed as an option by the rasteriser
+func absPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("pdfinput: %w", err)
+	}
+	return abs, nil
+}
+
+func command(path, name string) string {
+	if path != "" {
+		return path
+	}
+	return name
+}
+
+func run(ctx context.Context, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("pdfinput: %s: %w", filepath.Base(name), tesseract.ErrProcessTimeout)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("pdfinput: %s: %w: %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// pageFiles returns the page-N.png files in dir ordered by N
+func pageFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]int, len(matches))
+	for _, path := range matches {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "page-"), ".png"))
+		if err != nil {
+			continue
+		}
+		pages[path] = n
+	}
+
+	paths := make([]string, 0, len(pages))
+	for path := range pages {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return pages[paths[i]] < pages[paths[j]] })
+
+	if len(paths) == 0 {
+		return nil, ErrNoPages
+	}
+	return paths, nil
+}
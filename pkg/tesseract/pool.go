@@ -0,0 +1,554 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is returned for jobs submitted to a closed Pool
+var ErrPoolClosed = errors.New("tesseract pool is closed")
+
+// PoolConfig holds the options of a Pool
+type PoolConfig struct {
+	// Workers is the number of tesseract processes kept running; zero
+	// means runtime.NumCPU()
+	Workers int
+
+	// MaxJobs is the number of images a process recognises before it is
+	// replaced by a fresh one, which bounds its memory growth; zero means
+	// 1000
+	MaxJobs int
+}
+
+// stopTimeout is how long a process being recycled may take to finish
+// before it is killed
+const stopTimeout = 5 * time.Second
+
+// Pool keeps tesseract processes running to amortise their start-up cost
+// (process spawn plus loading the traineddata) over many small images.
+//
+// Each worker owns a long-lived tesseract process started with
+// stream_filelist set, which reads image paths from stdin and recognises
+// each as it arrives. The worker writes one image at a time and reads its
+// text back from stdout. A process serves jobs with one language and set
+// of options; a job with others makes the worker replace it.
+//
+// Processes are recycled after MaxJobs images. Before every job the worker
+// checks its process is still running and starts a new one if not. A
+// process that fails, on an unreadable image, a timeout or a crash, fails
+// only the job it was working on and is replaced for the next one.
+//
+// Image lists and multi-page TIFFs, whose page count is not known up
+// front, are recognised in a tesseract run of their own. A Pool needs
+// tesseract 4.0 or newer and runs it itself, so Config.Executor must not
+// be set. RunInfo of pooled jobs has no Warnings or Resolution, as their
+// process's stderr is shared by all its jobs.
+type Pool struct {
+	client *Client
+	config PoolConfig
+
+	mu      sync.RWMutex
+	closed  bool
+	quit    chan struct{}  // closed when Close starts
+	senders sync.WaitGroup // submit calls that may still send on jobs
+	jobs    chan *poolJob
+
+	// base is cancelled when Close gives up waiting, killing every process
+	base   context.Context
+	cancel context.CancelFunc
+
+	wg    sync.WaitGroup // workers
+	procs sync.WaitGroup // tesseract processes
+
+	// dir holds the blank page written after every image
+	dir      string
+	sentinel string
+}
+
+// poolJob is one image waiting for a worker
+type poolJob struct {
+	ctx  context.Context
+	in   Input
+	req  ocrRequest
+	key  string // jobs with the same key can share a process
+	done chan poolResult
+}
+
+type poolResult struct {
+	text string
+	err  error
+}
+
+// NewPool starts the workers running OCR with c. It fails if tesseract is
+// older than 4.0 or c has a custom Executor.
+func NewPool(c *Client, cfg PoolConfig) (*Pool, error) {
+	if c.config.Executor != nil {
+		return nil, fmt.Errorf("%w: a Pool runs tesseract itself and cannot use Config.Executor", ErrInvalidConfig)
+	}
+	// stream_filelist appeared in tesseract 4.0
+	if err := c.requireVersion("pool", Version{Major: 4}); err != nil {
+		return nil, err
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.MaxJobs <= 0 {
+		cfg.MaxJobs = 1000
+	}
+
+	dir, err := createTempDir()
+	if err != nil {
+		return nil, err
+	}
+	blank := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range blank.Pix {
+		blank.Pix[i] = 0xff
+	}
+	sentinel := filepath.Join(dir, "blank.png")
+	if err := saveImage(sentinel, blank); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	p := &Pool{
+		client:   c,
+		config:   cfg,
+		quit:     make(chan struct{}),
+		jobs:     make(chan *poolJob, cfg.Workers),
+		dir:      dir,
+		sentinel: sentinel,
+	}
+	p.base, p.cancel = context.WithCancel(context.Background())
+
+	p.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go p.worker()
+	}
+	return p, nil
+}
+
+// ImageToString queues img and returns its text once a worker has
+// recognised it, see Client.ImageToString
+func (p *Pool) ImageToString(ctx context.Context, img image.Image, lang string, opts ...Options) (string, error) {
+	return p.InputToString(ctx, ImageInput(img), lang, opts...)
+}
+
+// InputToString queues in and returns its text, without a trailing page
+// separator, once a worker has recognised it, see Client.InputToString.
+// If ctx ends first, the call returns ctx's error and the result is
+// discarded.
+func (p *Pool) InputToString(ctx context.Context, in Input, lang string, opts ...Options) (string, error) {
+	if err := in.validate(); err != nil {
+		return "", err
+	}
+	req, err := p.client.newRequest("stdout", lang, opts)
+	if err != nil {
+		return "", err
+	}
+	// Job texts are read back up to the page separator
+	if err := req.require("page_separator", "\f"); err != nil {
+		return "", err
+	}
+
+	job := &poolJob{
+		ctx:  ctx,
+		in:   in,
+		req:  req,
+		key:  strings.Join(req.args(""), "\x00"),
+		done: make(chan poolResult, 1),
+	}
+	if err := p.submit(ctx, job); err != nil {
+		return "", err
+	}
+
+	select {
+	case res := <-job.done:
+		return res.text, res.err
+	case <-ctx.Done():
+		return "", contextError(ctx)
+	}
+}
+
+// submit queues job unless the pool is closed. It does not hold the lock
+// while the queue is full, so Close can proceed and unblock it.
+func (p *Pool) submit(ctx context.Context, job *poolJob) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-p.quit:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+}
+
+// Close stops accepting jobs, waits for the queued ones to finish and
+// stops the tesseract processes. If ctx ends first, the processes are
+// killed, the remaining jobs fail and Close returns ctx's error.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	first := !p.closed
+	if first {
+		p.closed = true
+		close(p.quit)
+	}
+	p.mu.Unlock()
+
+	// jobs can only be closed once no submit is left sending on it
+	if first {
+		p.senders.Wait()
+		close(p.jobs)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		p.procs.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		err = ctx.Err()
+	}
+	p.cancel()
+	os.RemoveAll(p.dir)
+	return err
+}
+
+// worker runs jobs until the job queue is closed and drained
+func (p *Pool) worker() {
+	defer p.wg.Done()
+
+	var proc *poolProcess
+	defer func() {
+		if proc != nil {
+			proc.stop()
+		}
+	}()
+
+	for job := range p.jobs {
+		var res poolResult
+		switch {
+		case job.ctx.Err() != nil:
+			res.err = contextError(job.ctx)
+		case p.base.Err() != nil:
+			res.err = ErrPoolClosed
+		default:
+			res.text, res.err = p.run(job, &proc)
+		}
+		job.done <- res
+	}
+}
+
+// run recognises job, in *proc unless it needs a run of its own. *proc is
+// replaced when it cannot take job and dropped when it fails.
+func (p *Pool) run(job *poolJob, proc **poolProcess) (string, error) {
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	stop := context.AfterFunc(p.base, cancel)
+	defer stop()
+	ctx, cancelTimeout := p.client.withTimeout(ctx)
+	defer cancelTimeout()
+
+	if job.in.kind == inputList {
+		return p.runOne(ctx, job.in, job.req)
+	}
+
+	dir, err := createTempDir()
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	path, _, closeInput, err := job.in.source(dir, "input", false)
+	closeInput()
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(path, "\r\n") || isTIFF(path) {
+		return p.runOne(ctx, FileInput(path), job.req)
+	}
+	if job.req.lang != "" {
+		if err := p.client.validateLanguage(ctx, job.req.lang); err != nil {
+			return "", err
+		}
+	}
+
+	if cur := *proc; cur != nil && (cur.key != job.key || cur.jobs >= p.config.MaxJobs || !cur.running()) {
+		cur.stop()
+		*proc = nil
+	}
+	if *proc == nil {
+		if *proc, err = p.start(job); err != nil {
+			return "", err
+		}
+	}
+
+	start := time.Now()
+	text, err := (*proc).recognise(ctx, path, p.sentinel)
+	if job.req.info != nil {
+		res := &Execution{CommandLine: (*proc).commandLine}
+		if err != nil {
+			res.ExitCode = (*proc).exitCode()
+		}
+		p.client.fillRunInfo(ctx, job.req.info, p.client.binary(), nil, time.Since(start), res)
+	}
+	if err != nil {
+		(*proc).kill()
+		*proc = nil
+		return "", err
+	}
+	return text, nil
+}
+
+// runOne recognises in in a tesseract run of its own
+func (p *Pool) runOne(ctx context.Context, in Input, req ocrRequest) (string, error) {
+	res, err := p.client.runOCR(ctx, in, req)
+	if err != nil {
+		return "", err
+	}
+	return string(res.Stdout), nil
+}
+
+// isTIFF reports whether the image at path is a TIFF, which may hold
+// several pages
+func isTIFF(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(f, head); err != nil {
+		return false
+	}
+	return string(head) == "II*\x00" || string(head) == "MM\x00*"
+}
+
+// poolProcess is a tesseract process reading image paths from stdin
+type poolProcess struct {
+	key         string
+	commandLine []string
+	stdin       io.WriteCloser
+	stderr      tailBuffer
+	cancel      context.CancelFunc
+
+	// segments carries the text between page separators; it is closed
+	// when stdout ends
+	segments chan string
+
+	// done is closed once the process has exited and state is set
+	done  chan struct{}
+	state *os.ProcessState
+	err   error
+
+	// jobs counts the images written to the process
+	jobs int
+}
+
+// start launches a process for jobs like job
+func (p *Pool) start(job *poolJob) (*poolProcess, error) {
+	req := job.req
+	req.variables = maps.Clone(req.variables)
+	if err := req.require("stream_filelist", "1"); err != nil {
+		return nil, err
+	}
+
+	b := p.client.binary()
+	name, args, err := niceCommand(p.client.config.Nice, b.path, req.args("stdin"))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(p.base)
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessTree(cmd)
+	if len(p.client.env) > 0 {
+		cmd.Env = append(os.Environ(), p.client.env...)
+	}
+
+	proc := &poolProcess{
+		key:         job.key,
+		commandLine: append([]string{name}, args...),
+		cancel:      cancel,
+		segments:    make(chan string),
+		done:        make(chan struct{}),
+	}
+	cmd.Stderr = &proc.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, notFoundError(err)
+	}
+	proc.stdin = stdin
+
+	p.procs.Add(1)
+	go func() {
+		defer p.procs.Done()
+		defer cancel()
+		proc.read(ctx, stdout)
+		proc.err = cmd.Wait()
+		proc.state = cmd.ProcessState
+		close(proc.done)
+	}()
+	return proc, nil
+}
+
+// read splits stdout at the page separators until it ends. Segments nobody
+// waits for are dropped once ctx ends.
+func (proc *poolProcess) read(ctx context.Context, stdout io.Reader) {
+	defer close(proc.segments)
+	r := bufio.NewReader(stdout)
+	for {
+		segment, err := r.ReadString('\f')
+		if err != nil {
+			// Text after the last separator belongs to an unfinished page
+			io.Copy(io.Discard, r)
+			return
+		}
+		select {
+		case proc.segments <- strings.TrimSuffix(segment, "\f"):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// recognise writes path, followed by the blank sentinel page, and returns
+// the text of path. The sentinel makes tesseract write the separator that
+// ends path's text: tesseract 5 writes separators between pages rather than
+// after them. Its own text is read and dropped before the next image's.
+func (proc *poolProcess) recognise(ctx context.Context, path, sentinel string) (string, error) {
+	if _, err := io.WriteString(proc.stdin, path+"\n"+sentinel+"\n"); err != nil {
+		return "", proc.failure(ctx)
+	}
+	proc.jobs++
+	if proc.jobs > 1 {
+		if _, err := proc.next(ctx); err != nil {
+			return "", err
+		}
+	}
+	return proc.next(ctx)
+}
+
+// next returns the next segment of the output
+func (proc *poolProcess) next(ctx context.Context) (string, error) {
+	select {
+	case segment, ok := <-proc.segments:
+		if !ok {
+			return "", proc.failure(ctx)
+		}
+		return segment, nil
+	case <-ctx.Done():
+		return "", contextError(ctx)
+	}
+}
+
+// failure waits for the process to exit and reports why it did
+func (proc *poolProcess) failure(ctx context.Context) error {
+	select {
+	case <-proc.done:
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+	if proc.state == nil {
+		return proc.err
+	}
+	return newOCRError(proc.state.ExitCode(), proc.stderr.Bytes())
+}
+
+// exitCode returns the exit status of a process that has exited, or -1
+func (proc *poolProcess) exitCode() int {
+	if !proc.running() && proc.state != nil {
+		return proc.state.ExitCode()
+	}
+	return -1
+}
+
+// running reports whether the process has not exited
+func (proc *poolProcess) running() bool {
+	select {
+	case <-proc.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop closes the process's stdin so it exits once it has finished the
+// sentinel page, killing it if it takes longer than stopTimeout
+func (proc *poolProcess) stop() {
+	proc.stdin.Close()
+	go func() {
+		for range proc.segments {
+		}
+	}()
+	timer := time.AfterFunc(stopTimeout, proc.cancel)
+	go func() {
+		<-proc.done
+		timer.Stop()
+	}()
+}
+
+// kill ends the process at once
+func (proc *poolProcess) kill() {
+	proc.stdin.Close()
+	proc.cancel()
+}
+
+// tailBuffer keeps the last tailSize bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+const tailSize = 64 << 10
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > tailSize {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-tailSize:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf...)
+}
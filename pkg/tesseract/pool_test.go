@@ -0,0 +1,251 @@
+package tesseract_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// usePoolFixtures makes fakeBinary report version and print each image's
+// size, and returns the file its invocations are logged to
+func usePoolFixtures(t *testing.T, version string, files map[string]string) string {
+	t.Helper()
+	fixtures := map[string]string{
+		"version.txt": "tesseract " + version + "\n leptonica-1.82.0\n",
+		"output.txt":  "{width}x{height}\n",
+	}
+	for name, data := range files {
+		fixtures[name] = data
+	}
+	useFixtures(t, fixtures)
+	log := filepath.Join(t.TempDir(), "calls")
+	t.Setenv("TESSERACTTEST_LOG", log)
+	return log
+}
+
+// processes returns how many tesseract processes streaming image paths
+// have been started
+func processes(t *testing.T, log string) int {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "stream_filelist=1")
+}
+
+// sizedImage returns a blank image width pixels wide and 10 high
+func sizedImage(width int) image.Image {
+	return image.NewGray(image.Rect(0, 0, width, 10))
+}
+
+// pngData encodes img as PNG
+func pngData(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPool(t *testing.T) {
+	// Tesseract 4 writes the page separator after every page, 5 between
+	// pages
+	for _, version := range []string{"4.1.1", "5.3.0"} {
+		t.Run(version, func(t *testing.T) {
+			log := usePoolFixtures(t, version, nil)
+			client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+			pool, err := tesseract.NewPool(client, tesseract.PoolConfig{Workers: 2, MaxJobs: 3})
+			if err != nil {
+				t.Fatalf("NewPool() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					width := 10 + i
+					text, err := pool.ImageToString(context.Background(), sizedImage(width), "eng")
+					if want := fmt.Sprintf("%dx10\n", width); err != nil || text != want {
+						t.Errorf("ImageToString(%d wide) = %q, %v; want %q", width, text, err, want)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if err := pool.Close(context.Background()); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			// Each worker's process is recycled after 3 images
+			if n := processes(t, log); n < 3 || n > 4 {
+				t.Errorf("started %d tesseract processes for 8 images, want 3 or 4", n)
+			}
+			if _, err := pool.ImageToString(context.Background(), sizedImage(10), "eng"); !errors.Is(err, tesseract.ErrPoolClosed) {
+				t.Errorf("ImageToString() after Close error = %v, want %v", err, tesseract.ErrPoolClosed)
+			}
+		})
+	}
+}
+
+func TestPoolRestart(t *testing.T) {
+	log := usePoolFixtures(t, "5.3.0", nil)
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+	pool, err := tesseract.NewPool(client, tesseract.PoolConfig{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close(context.Background())
+
+	// A corrupt image kills its process, which only fails that job
+	corrupt := tesseract.ReaderInput(strings.NewReader("\x89PNG\r\n\x1a\ncorrupt"), "")
+	tests := []struct {
+		in   tesseract.Input
+		lang string
+		want string
+	}{
+		{in: tesseract.ReaderInput(bytes.NewReader(pngData(t, sizedImage(11))), ""), lang: "eng", want: "11x10\n"},
+		{in: corrupt, lang: "eng"},
+		{in: tesseract.ReaderInput(bytes.NewReader(pngData(t, sizedImage(12))), ""), lang: "eng", want: "12x10\n"},
+		{in: tesseract.ImageInput(sizedImage(13)), lang: "eng", want: "13x10\n"},
+		// Another language needs another process
+		{in: tesseract.ImageInput(sizedImage(14)), lang: "fra", want: "14x10\n"},
+	}
+	for i, tt := range tests {
+		text, err := pool.InputToString(context.Background(), tt.in, tt.lang)
+		if tt.want == "" {
+			if !errors.Is(err, tesseract.ErrInvalidImage) {
+				t.Errorf("job %d error = %v, want %v", i, err, tesseract.ErrInvalidImage)
+			}
+			continue
+		}
+		if err != nil || text != tt.want {
+			t.Errorf("job %d = %q, %v; want %q", i, text, err, tt.want)
+		}
+	}
+
+	if n := processes(t, log); n != 3 {
+		t.Errorf("started %d tesseract processes, want 3", n)
+	}
+}
+
+func TestPoolTimeout(t *testing.T) {
+	dir := fixtureDir(t, map[string]string{
+		"output.txt": "{width}x{height}\n",
+		"delay.txt":  "10s",
+	})
+	t.Setenv("TESSERACTTEST_FIXTURES", dir)
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary, Timeout: 200 * time.Millisecond})
+	pool, err := tesseract.NewPool(client, tesseract.PoolConfig{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close(context.Background())
+
+	var info tesseract.RunInfo
+	_, err = pool.ImageToString(context.Background(), sizedImage(11), "eng", tesseract.Options{Info: &info})
+	if !tesseract.IsTimeout(err) {
+		t.Errorf("ImageToString() error = %v, want a timeout", err)
+	}
+	if !strings.Contains(strings.Join(info.CommandLine, " "), "stream_filelist=1") {
+		t.Errorf("RunInfo.CommandLine = %q, want the streaming process", info.CommandLine)
+	}
+
+	// The hung process is replaced for the next job
+	if err := os.Remove(filepath.Join(dir, "delay.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := pool.ImageToString(context.Background(), sizedImage(12), "eng"); err != nil || text != "12x10\n" {
+		t.Errorf("ImageToString() after a timeout = %q, %v; want %q", text, err, "12x10\n")
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+	pool, err := tesseract.NewPool(client, tesseract.PoolConfig{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := pool.ImageToString(context.Background(), testImage(), "")
+		result <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := pool.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close() returned after %v, want running processes killed", elapsed)
+	}
+	if err := <-result; err == nil {
+		t.Error("ImageToString() interrupted by Close succeeded")
+	}
+}
+
+func TestPoolCloseFullQueue(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+	pool, err := tesseract.NewPool(client, tesseract.PoolConfig{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+
+	// one job runs, one fills the queue and the rest block in submit
+	results := make(chan error, 4)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			_, err := pool.ImageToString(context.Background(), testImage(), "")
+			results <- err
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := pool.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close() returned after %v, want it not to wait for queue space", elapsed)
+	}
+	for i := 0; i < cap(results); i++ {
+		if err := <-results; err == nil {
+			t.Error("ImageToString() interrupted by Close succeeded")
+		}
+	}
+}
+
+func TestNewPoolErrors(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	if _, err := tesseract.NewPool(newClient(t, tesseract.Config{Executor: exec}), tesseract.PoolConfig{}); !errors.Is(err, tesseract.ErrInvalidConfig) {
+		t.Errorf("NewPool() with an Executor error = %v, want %v", err, tesseract.ErrInvalidConfig)
+	}
+
+	// stream_filelist needs tesseract 4
+	usePoolFixtures(t, "3.05.02", nil)
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+	if _, err := tesseract.NewPool(client, tesseract.PoolConfig{}); !errors.Is(err, tesseract.ErrFeatureUnsupported) {
+		t.Errorf("NewPool() on tesseract 3 error = %v, want %v", err, tesseract.ErrFeatureUnsupported)
+	}
+}
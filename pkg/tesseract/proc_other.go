@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package tesseract
+
+import (
+	"os/exec"
+	"time"
+)
+
+// killProcessTree bounds how long cmd may linger after cancellation;
+// process groups are not available on this platform
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.WaitDelay = time.Second
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package tesseract
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// killProcessTree starts cmd in its own process group and, on context
+// cancellation, kills the whole group so helpers spawned by tesseract
+// do not outlive it or keep its output pipes open
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+}
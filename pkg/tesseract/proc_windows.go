@@ -0,0 +1,22 @@
+//go:build windows
+
+package tesseract
+
+import (
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// killProcessTree makes context cancellation terminate cmd together with
+// any child processes it started
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = time.Second
+}
@@ -0,0 +1,222 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract/alto"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/hocr"
+)
+
+// Format names an output Tesseract can render during Recognize
+type Format string
+
+const (
+	// FormatText renders plain text
+	FormatText Format = "txt"
+
+	// FormatHOCR renders hOCR
+	FormatHOCR Format = "hocr"
+
+	// FormatTSV renders word-level TSV records
+	FormatTSV Format = "tsv"
+
+	// FormatPDF renders a searchable PDF
+	FormatPDF Format = "pdf"
+
+	// FormatALTO renders ALTO XML (Tesseract 4.1 or newer)
+	FormatALTO Format = "alto"
+
+	// FormatBox renders a character box file
+	FormatBox Format = "box"
+
+	// FormatLSTMBox renders an LSTM training box file (Tesseract 4.0 or
+	// newer)
+	FormatLSTMBox Format = "lstmbox"
+
+	// FormatWordStrBox renders a WordStr box file (Tesseract 4.0 or newer)
+	FormatWordStrBox Format = "wordstrbox"
+)
+
+// formatOutput describes how a Format is requested and where it is written
+type formatOutput struct {
+	config   string  // tesseract config file enabling the renderer
+	variable string  // variable the config sets to enable the renderer
+	suffix   string  // suffix of the file written next to the output base
+	version  Version // oldest tesseract with the renderer
+}
+
+// formats lists the supported formats in the order their configs are passed
+var formats = []Format{
+	FormatText, FormatHOCR, FormatTSV, FormatPDF, FormatALTO,
+	FormatBox, FormatLSTMBox, FormatWordStrBox,
+}
+
+var formatOutputs = map[Format]formatOutput{
+	FormatText:       {"txt", "tessedit_create_txt", "txt", Version{3, 5, 0}},
+	FormatHOCR:       {"hocr", "tessedit_create_hocr", "hocr", Version{3, 5, 0}},
+	FormatTSV:        {"tsv", "tessedit_create_tsv", "tsv", Version{3, 5, 0}},
+	FormatPDF:        {"pdf", "tessedit_create_pdf", "pdf", Version{3, 5, 0}},
+	FormatALTO:       {"alto", "tessedit_create_alto", "xml", Version{4, 1, 0}},
+	FormatBox:        {"makebox", "tessedit_create_boxfile", "box", Version{3, 5, 0}},
+	FormatLSTMBox:    {"lstmbox", "tessedit_create_lstmbox", "box", Version{4, 0, 0}},
+	FormatWordStrBox: {"wordstrbox", "tessedit_create_wordstrbox", "box", Version{4, 0, 0}},
+}
+
+// requireFormat adds the renderer config for f. It fails with
+// ErrVariableConflict if the caller's variables turn the renderer off.
+func (r *ocrRequest) requireFormat(f Format) error {
+	out := formatOutputs[f]
+	if err := r.require(out.variable, "1"); err != nil {
+		return err
+	}
+	r.configs = append(r.configs, out.config)
+	return nil
+}
+
+// Result holds the outputs of a single Recognize run. Only the fields of
+// the requested formats are set.
+type Result struct {
+	// Text is the FormatText output
+	Text string
+
+	// HOCR is the parsed FormatHOCR output
+	HOCR *hocr.Document
+
+	// Words are the parsed FormatTSV records
+	Words []Word
+
+	// PDF is the FormatPDF output
+	PDF []byte
+
+	// ALTO is the parsed FormatALTO output
+	ALTO *alto.Document
+
+	// Boxes are the parsed FormatBox or FormatLSTMBox records
+	Boxes []Box
+
+	// Raw holds the unparsed file contents of every requested format,
+	// including FormatWordStrBox which has no parsed form
+	Raw map[Format][]byte
+}
+
+// Recognize performs OCR once and renders every requested format from
+// that single tesseract run
+func (c *Client) Recognize(img image.Image, lang string, requested []Format, opts ...Options) (*Result, error) {
+	return c.RecognizeContext(context.Background(), img, lang, requested, opts...)
+}
+
+// RecognizeContext is like Recognize but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) RecognizeContext(ctx context.Context, img image.Image, lang string, requested []Format, opts ...Options) (*Result, error) {
+	return c.RecognizeInputContext(ctx, ImageInput(img), lang, requested, opts...)
+}
+
+// RecognizeInput is like Recognize for any Input
+func (c *Client) RecognizeInput(in Input, lang string, requested []Format, opts ...Options) (*Result, error) {
+	return c.RecognizeInputContext(context.Background(), in, lang, requested, opts...)
+}
+
+// RecognizeInputContext is like RecognizeInput but stops the tesseract
+// process when ctx is cancelled or its deadline expires
+func (c *Client) RecognizeInputContext(ctx context.Context, in Input, lang string, requested []Format, opts ...Options) (*Result, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	selected, err := selectFormats(requested)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range selected {
+		if err := c.requireVersion(string(f)+" output", formatOutputs[f].version); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req, err := c.newRequest("", lang, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range selected {
+		if err := req.requireFormat(f); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.runOCR(ctx, in, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResult(res.Files, selected)
+}
+
+// selectFormats validates requested and returns it deduplicated in the
+// order of formats
+func selectFormats(requested []Format) ([]Format, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("%w: no output formats requested", ErrInvalidConfig)
+	}
+
+	want := make(map[Format]bool, len(requested))
+	for _, f := range requested {
+		if _, ok := formatOutputs[f]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedExtension, f)
+		}
+		want[f] = true
+	}
+
+	var selected []Format
+	suffixes := make(map[string]Format)
+	for _, f := range formats {
+		if !want[f] {
+			continue
+		}
+		suffix := formatOutputs[f].suffix
+		if other, ok := suffixes[suffix]; ok {
+			return nil, fmt.Errorf("%w: %s and %s both write a .%s file", ErrInvalidConfig, other, f, suffix)
+		}
+		suffixes[suffix] = f
+		selected = append(selected, f)
+	}
+	return selected, nil
+}
+
+// readResult reads and parses the output files of the selected formats
+func readResult(files map[string][]byte, selected []Format) (*Result, error) {
+	res := &Result{Raw: make(map[Format][]byte, len(selected))}
+	for _, f := range selected {
+		data, err := readOutput(files, formatOutputs[f].suffix)
+		if err != nil {
+			return nil, err
+		}
+		res.Raw[f] = data
+
+		switch f {
+		case FormatText:
+			res.Text = string(data)
+		case FormatHOCR:
+			res.HOCR, err = hocr.Parse(bytes.NewReader(data))
+		case FormatTSV:
+			res.Words, err = parseWords(string(data))
+		case FormatPDF:
+			res.PDF = data
+		case FormatALTO:
+			res.ALTO, err = alto.Decode(bytes.NewReader(data))
+		case FormatBox, FormatLSTMBox:
+			res.Boxes, err = parseBoxes(bytes.NewReader(data))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package tesseract_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+// renderer returns a response that writes an output file for every
+// renderer config in a run's arguments
+func renderer(t testing.TB) func(tesseracttest.Call) tesseract.Execution {
+	t.Helper()
+	hocr, err := os.ReadFile("hocr/testdata/sample.hocr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alto, err := os.ReadFile("alto/testdata/sample.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := map[string]struct {
+		suffix string
+		data   []byte
+	}{
+		"txt":        {"txt", []byte("Fish&Chips\n")},
+		"hocr":       {"hocr", hocr},
+		"tsv":        {"tsv", fixture(t, "output.tsv")},
+		"pdf":        {"pdf", []byte("%PDF-1.5")},
+		"alto":       {"xml", alto},
+		"makebox":    {"box", []byte("F 36 140 60 180 0\n")},
+		"wordstrbox": {"box", []byte("WordStr 36 140 604 180 0 #Fish&Chips\n")},
+	}
+
+	return func(call tesseracttest.Call) tesseract.Execution {
+		files := make(map[string][]byte)
+		for _, arg := range call.Args[2:] {
+			if out, ok := outputs[arg]; ok {
+				files[out.suffix] = out.data
+			}
+		}
+		return tesseract.Execution{Files: files}
+	}
+}
+
+func TestRecognize(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(renderer(t))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	res, err := client.Recognize(testImage(), "", []tesseract.Format{
+		tesseract.FormatBox, tesseract.FormatPDF, tesseract.FormatText, tesseract.FormatHOCR,
+		tesseract.FormatTSV, tesseract.FormatALTO, tesseract.FormatText,
+	})
+	if err != nil {
+		t.Fatalf("Recognize() error = %v", err)
+	}
+
+	if n := len(runs(exec)); n != 1 {
+		t.Fatalf("tesseract ran %d times, want 1", n)
+	}
+	if args := strings.Join(runArgs(exec), " "); !strings.HasSuffix(args, " txt hocr tsv pdf alto makebox") {
+		t.Errorf("command line = %q, want configs in format order", args)
+	}
+
+	if res.Text != "Fish&Chips\n" {
+		t.Errorf("Text = %q", res.Text)
+	}
+	if len(res.HOCR.Pages) != 1 {
+		t.Errorf("HOCR has %d pages, want 1", len(res.HOCR.Pages))
+	}
+	if len(res.Words) != 12 {
+		t.Errorf("Words has %d records, want 12", len(res.Words))
+	}
+	if string(res.PDF) != "%PDF-1.5" {
+		t.Errorf("PDF = %q", res.PDF)
+	}
+	if len(res.ALTO.Layout.Pages) != 1 {
+		t.Errorf("ALTO has %d pages, want 1", len(res.ALTO.Layout.Pages))
+	}
+	want := []tesseract.Box{{Char: 'F', Left: 36, Bottom: 140, Right: 60, Top: 180}}
+	if !reflect.DeepEqual(res.Boxes, want) {
+		t.Errorf("Boxes = %+v, want %+v", res.Boxes, want)
+	}
+	if len(res.Raw) != 6 {
+		t.Errorf("Raw has %d formats, want 6", len(res.Raw))
+	}
+}
+
+func TestRecognizeInvalidFormats(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(renderer(t))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	tests := []struct {
+		name    string
+		formats []tesseract.Format
+		wantErr error
+	}{
+		{"None", nil, tesseract.ErrInvalidConfig},
+		{"Unknown", []tesseract.Format{"docx"}, tesseract.ErrUnsupportedExtension},
+		{"Box clash", []tesseract.Format{tesseract.FormatBox, tesseract.FormatWordStrBox}, tesseract.ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Recognize(testImage(), "", tt.formats); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Recognize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecognizeVariableConflict(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.RespondFunc(renderer(t))
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	opts := tesseract.Options{Variables: map[string]string{"tessedit_create_tsv": "0"}}
+	_, err := client.Recognize(testImage(), "", []tesseract.Format{tesseract.FormatText, tesseract.FormatTSV}, opts)
+	if !errors.Is(err, tesseract.ErrVariableConflict) {
+		t.Errorf("Recognize() with tessedit_create_tsv=0 error = %v, want %v", err, tesseract.ErrVariableConflict)
+	}
+	if n := len(runs(exec)); n != 0 {
+		t.Errorf("tesseract ran %d times, want 0", n)
+	}
+}
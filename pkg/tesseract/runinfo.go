@@ -0,0 +1,84 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RunInfo describes a tesseract run. Request it by passing
+// Options{Info: &info} to any OCR call.
+type RunInfo struct {
+	// CommandLine is the program and arguments that were started, which
+	// begins with nice(1) when Config.Nice is set
+	CommandLine []string
+
+	// Version is the tesseract version that ran
+	Version Version
+
+	// Duration is the wall-clock time of the run
+	Duration time.Duration
+
+	// ExitCode is the process exit status, or -1 if the process did not
+	// exit by itself: it could not be started, or was killed because the
+	// context ended or Config.Timeout expired
+	ExitCode int
+
+	// Warnings are the non-empty lines tesseract printed on stderr, such
+	// as "Too few characters. Skipping this page"
+	Warnings []string
+
+	// Resolution is the resolution in dpi tesseract estimated or fell back
+	// to because the image had none; zero if it used the image's own
+	Resolution int
+}
+
+var resolutionRegex = regexp.MustCompile(`(?:Estimating resolution as|Invalid resolution \d+ dpi\. Using) (\d+)`)
+
+// fillRunInfo records a run under ctx in info. res may be nil if the
+// process could not be started. The command line is the one the Executor
+// reports, or else b's path followed by args.
+func (c *Client) fillRunInfo(ctx context.Context, info *RunInfo, b *binary, args []string, d time.Duration, res *Execution) {
+	*info = RunInfo{
+		CommandLine: append([]string{b.path}, args...),
+		Duration:    d,
+		ExitCode:    -1,
+	}
+	if res != nil && res.CommandLine != nil {
+		info.CommandLine = res.CommandLine
+	}
+	if v, err := c.tesseractVersion(); err == nil {
+		info.Version = v.Tesseract
+	}
+	if res == nil {
+		return
+	}
+
+	if ctx.Err() == nil {
+		info.ExitCode = res.ExitCode
+	}
+	info.Warnings, info.Resolution = parseWarnings(string(res.Stderr))
+}
+
+// parseWarnings splits stderr into lines and extracts the resolution
+// tesseract reports using
+func parseWarnings(stderr string) ([]string, int) {
+	var (
+		warnings   []string
+		resolution int
+	)
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		warnings = append(warnings, line)
+		if m := resolutionRegex.FindStringSubmatch(line); m != nil {
+			resolution, _ = strconv.Atoi(m[1])
+		}
+	}
+	return warnings, resolution
+}
@@ -0,0 +1,95 @@
+package tesseract_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestRunInfo(t *testing.T) {
+	useFixtures(t, map[string]string{"stderr.txt": "Warning: Invalid resolution 0 dpi. Using 70 instead.\n" +
+		"Estimating resolution as 301\n" +
+		"Too few characters. Skipping this page\n"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary, Language: "eng"})
+
+	var info tesseract.RunInfo
+	if _, err := client.ImageToString(testImage(), "", tesseract.Options{Info: &info}); err != nil {
+		t.Fatalf("ImageToString() error = %v", err)
+	}
+
+	wantWarnings := []string{
+		"Warning: Invalid resolution 0 dpi. Using 70 instead.",
+		"Estimating resolution as 301",
+		"Too few characters. Skipping this page",
+	}
+	if !reflect.DeepEqual(info.Warnings, wantWarnings) {
+		t.Errorf("Warnings = %q, want %q", info.Warnings, wantWarnings)
+	}
+	if info.Resolution != 301 {
+		t.Errorf("Resolution = %d, want 301", info.Resolution)
+	}
+	if info.Version != (tesseract.Version{Major: 5, Minor: 3, Patch: 0}) {
+		t.Errorf("Version = %v, want 5.3.0", info.Version)
+	}
+	if len(info.CommandLine) != 5 || info.CommandLine[0] != fakeBinary || info.CommandLine[2] != "stdout" || info.CommandLine[4] != "eng" {
+		t.Errorf("CommandLine = %q", info.CommandLine)
+	}
+	if info.Duration <= 0 || info.ExitCode != 0 {
+		t.Errorf("Duration = %v, ExitCode = %d", info.Duration, info.ExitCode)
+	}
+}
+
+func TestRunInfoNice(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Config.Nice is Linux only")
+	}
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary, Nice: 5})
+
+	var info tesseract.RunInfo
+	if _, err := client.ImageToString(testImage(), "eng", tesseract.Options{Info: &info}); err != nil {
+		t.Fatalf("ImageToString() error = %v", err)
+	}
+	want := []string{"nice", "-n", "5", fakeBinary}
+	if len(info.CommandLine) != 8 || !reflect.DeepEqual(info.CommandLine[:4], want) || info.CommandLine[5] != "stdout" {
+		t.Errorf("CommandLine = %q, want it to start with %q", info.CommandLine, want)
+	}
+}
+
+func TestRunInfoOnFailure(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{ExitCode: 1, Stderr: []byte("Error in pixReadStream: Unknown format\n")})
+	client := newClient(t, tesseract.Config{Executor: exec})
+
+	var info tesseract.RunInfo
+	if _, err := client.ImageToData(testImage(), "eng", tesseract.Options{Info: &info}); err == nil {
+		t.Fatal("ImageToData() succeeded, want error")
+	}
+	if info.ExitCode != 1 || len(info.Warnings) != 1 {
+		t.Errorf("RunInfo = %+v, want exit code 1 and the error line", info)
+	}
+}
+
+func TestRunInfoCancelled(t *testing.T) {
+	useFixtures(t, map[string]string{"delay.txt": "10s"})
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	var info tesseract.RunInfo
+	if _, err := client.ImageToStringContext(ctx, testImage(), "eng", tesseract.Options{Info: &info}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ImageToStringContext() error = %v, want %v", err, context.Canceled)
+	}
+	if info.ExitCode != -1 {
+		t.Errorf("ExitCode = %d, want -1 for a killed run", info.ExitCode)
+	}
+}
@@ -0,0 +1,80 @@
+package tesseract_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestUseStdin(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"hocr": []byte("<html/>\n")}}, "tessedit_create_hocr=1")
+	exec.Respond(tesseract.Execution{Stdout: []byte("text\n")}, "stdout")
+	client := newClient(t, tesseract.Config{Executor: exec, UseStdin: true})
+	img := testImage()
+
+	text, err := client.ImageToString(img, "")
+	if err != nil || text != "text\n" {
+		t.Fatalf("ImageToString() = %q, %v", text, err)
+	}
+	call := runs(exec)[0]
+	if call.Args[0] != "stdin" {
+		t.Errorf("image argument = %q, want stdin", call.Args[0])
+	}
+	if _, err := png.Decode(bytes.NewReader(call.Stdin)); err != nil {
+		t.Errorf("stdin is not a PNG: %v", err)
+	}
+
+	// Outputs written next to an output base still work
+	out, err := client.ImageToExtension(img, "eng", "hocr")
+	if err != nil || out != "<html/>\n" {
+		t.Errorf("ImageToExtension() = %q, %v", out, err)
+	}
+}
+
+func TestUseStdinEarlyExit(t *testing.T) {
+	// tesseract failing to load a language before it reads the image must
+	// not block the encoder
+	client := newClient(t, tesseract.Config{TesseractPath: fakeBinary, UseStdin: true})
+	if _, err := client.ImageToString(benchImage(), "deu"); err == nil {
+		t.Error("ImageToString() succeeded, want error")
+	}
+}
+
+// benchImage returns a noisy page-sized image that does not compress away
+func benchImage() image.Image {
+	r := rand.New(rand.NewSource(1))
+	img := image.NewGray(image.Rect(0, 0, 1240, 1754))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(r.Intn(256))
+	}
+	return img
+}
+
+func BenchmarkImageToString(b *testing.B) {
+	img := benchImage()
+
+	for _, mode := range []struct {
+		name  string
+		stdin bool
+	}{
+		{"TempFile", false},
+		{"Stdin", true},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			client := newClient(b, tesseract.Config{TesseractPath: fakeBinary, UseStdin: mode.stdin})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.ImageToString(img, ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
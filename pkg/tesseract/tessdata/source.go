@@ -0,0 +1,161 @@
+package tessdata
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumFile is the name of the optional sha256sum(1) style file a
+// source publishes next to the traineddata files of each variant
+const ChecksumFile = "SHA256SUMS"
+
+// Source provides traineddata files to a Manager
+type Source interface {
+	// Open returns the traineddata file of lang, e.g. "eng" or
+	// "script/Latin", in variant. It fails with ErrNotFound if the source
+	// has no such file.
+	Open(ctx context.Context, variant Variant, lang string) (io.ReadCloser, error)
+
+	// Checksums returns the SHA-256 sums the source publishes for
+	// variant, keyed by language, or nil if it publishes none
+	Checksums(ctx context.Context, variant Variant) (map[string]string, error)
+}
+
+// Dir is a local mirror laid out like the upstream repositories:
+// tessdata_fast/eng.traineddata, tessdata_best/script/Latin.traineddata,
+// tessdata/osd.traineddata and an optional SHA256SUMS in each
+type Dir string
+
+// Open implements Source
+func (d Dir) Open(ctx context.Context, variant Variant, lang string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(string(d), variant.Repository(), filepath.FromSlash(lang)+Ext))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, variant, lang)
+	}
+	return f, err
+}
+
+// Checksums implements Source
+func (d Dir) Checksums(ctx context.Context, variant Variant) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(string(d), variant.Repository(), ChecksumFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseChecksums(f)
+}
+
+// HTTP downloads from a web server with the same layout as Dir, below
+// BaseURL. Upstream points it at the tesseract-ocr GitHub repositories.
+type HTTP struct {
+	// BaseURL is the address of the mirror, without a trailing slash
+	BaseURL string
+
+	// Ref, if set, is inserted after the repository name, as the branch
+	// is in GitHub raw URLs
+	Ref string
+
+	// Client sends the requests; nil uses http.DefaultClient
+	Client *http.Client
+}
+
+// Upstream downloads from the official tesseract-ocr repositories on
+// GitHub. They publish no checksums, so installs from it need
+// Config.Checksums or Config.AllowUnverified.
+var Upstream = &HTTP{BaseURL: "https://raw.githubusercontent.com/tesseract-ocr", Ref: "main"}
+
+// Open implements Source
+func (h *HTTP) Open(ctx context.Context, variant Variant, lang string) (io.ReadCloser, error) {
+	body, err := h.get(ctx, variant, lang+Ext)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotFound, variant, lang)
+	}
+	return body, nil
+}
+
+// Checksums implements Source
+func (h *HTTP) Checksums(ctx context.Context, variant Variant) (map[string]string, error) {
+	body, err := h.get(ctx, variant, ChecksumFile)
+	if err != nil || body == nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ParseChecksums(body)
+}
+
+// get fetches name from the variant's repository. A 404 returns a nil
+// body and no error.
+func (h *HTTP) get(ctx context.Context, variant Variant, name string) (io.ReadCloser, error) {
+	parts := []string{strings.TrimSuffix(h.BaseURL, "/"), variant.Repository()}
+	if h.Ref != "" {
+		parts = append(parts, h.Ref)
+	}
+	url := strings.Join(append(parts, name), "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, nil
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("tessdata: GET %s: %s", url, resp.Status)
+	}
+}
+
+// ParseChecksums reads sha256sum(1) output such as
+// "<hex>  eng.traineddata" or "<hex> *script/Latin.traineddata" and
+// returns the sums keyed by language. Lines for other files are ignored.
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok || !validChecksum(sum) {
+			return nil, fmt.Errorf("tessdata: checksum line %d: malformed %q", n, line)
+		}
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		if lang, ok := strings.CutSuffix(filepath.ToSlash(name), Ext); ok {
+			sums[lang] = strings.ToLower(sum)
+		}
+	}
+	return sums, scanner.Err()
+}
+
+// validChecksum reports whether sum is a SHA-256 sum in hex
+func validChecksum(sum string) bool {
+	b, err := hex.DecodeString(sum)
+	return err == nil && len(b) == sha256.Size
+}
@@ -0,0 +1,375 @@
+// Package tessdata installs, lists, removes and verifies the traineddata
+// files Tesseract loads languages from. Files come from a Source, a local
+// mirror or a web server, are checked against their SHA-256 sums and
+// replace any previous version atomically.
+package tessdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+)
+
+// Ext is the file extension of language packs
+const Ext = ".traineddata"
+
+// Variant selects one of the upstream traineddata collections
+type Variant string
+
+const (
+	// Fast are the integer LSTM models of tessdata_fast, the default
+	Fast Variant = "fast"
+
+	// Best are the float LSTM models of tessdata_best: slower, slightly
+	// more accurate, and the only ones that can be fine-tuned
+	Best Variant = "best"
+
+	// Legacy are the models of the tessdata repository, which also
+	// include the legacy engine needed by OEMTesseractOnly
+	Legacy Variant = "legacy"
+)
+
+// Repository returns the name of the upstream repository, which is also
+// the variant's directory in a mirror
+func (v Variant) Repository() string {
+	switch v {
+	case Fast:
+		return "tessdata_fast"
+	case Best:
+		return "tessdata_best"
+	default:
+		return "tessdata"
+	}
+}
+
+var (
+	// ErrNotFound indicates the source has no file for the language
+	ErrNotFound = errors.New("tessdata: language pack not found in source")
+
+	// ErrNotInstalled indicates the language is not in the target directory
+	ErrNotInstalled = errors.New("tessdata: language pack not installed")
+
+	// ErrChecksumMismatch indicates a file does not match its SHA-256 sum
+	ErrChecksumMismatch = errors.New("tessdata: checksum mismatch")
+
+	// ErrNoChecksum indicates no SHA-256 sum is known for the language
+	ErrNoChecksum = errors.New("tessdata: no checksum for language pack")
+)
+
+// Config holds the options of a Manager
+type Config struct {
+	// Dir is the tessdata directory packs are installed into; give the
+	// client the same directory as tesseract.Config.TessdataDir
+	Dir string
+
+	// Source provides the files; nil uses Upstream
+	Source Source
+
+	// Variant selects the collection to install from; empty means Fast
+	Variant Variant
+
+	// Checksums pins the SHA-256 sums of packs by language, e.g. "eng"
+	// or "script/Latin", as 64 hex digits. They take precedence over the
+	// sums the source publishes.
+	Checksums map[string]string
+
+	// AllowUnverified installs packs no checksum is known for. Files with
+	// a known checksum are always verified.
+	AllowUnverified bool
+}
+
+// Pack describes an installed language pack
+type Pack struct {
+	// Lang is the language as passed to tesseract -l
+	Lang string
+
+	// Path is the traineddata file
+	Path string
+
+	// Size is the file size in bytes
+	Size int64
+
+	// ModTime is when the file was installed
+	ModTime time.Time
+}
+
+// Manager installs and maintains the language packs of one tessdata
+// directory. A Manager is safe for concurrent use and implements
+// tesseract.Installer.
+type Manager struct {
+	config Config
+
+	mu   sync.Mutex
+	sums map[string]string
+}
+
+var _ tesseract.Installer = (*Manager)(nil)
+
+// NewManager creates a Manager for cfg.Dir
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("tessdata: no target directory")
+	}
+	switch cfg.Variant {
+	case "":
+		cfg.Variant = Fast
+	case Fast, Best, Legacy:
+	default:
+		return nil, fmt.Errorf("tessdata: unknown variant %q", cfg.Variant)
+	}
+	if cfg.Source == nil {
+		cfg.Source = Upstream
+	}
+	if cfg.Checksums != nil {
+		sums := make(map[string]string, len(cfg.Checksums))
+		for lang, sum := range cfg.Checksums {
+			if !validChecksum(sum) {
+				return nil, fmt.Errorf("tessdata: checksum for %s: malformed %q, want 64 hex digits", lang, sum)
+			}
+			sums[lang] = strings.ToLower(sum)
+		}
+		cfg.Checksums = sums
+	}
+	return &Manager{config: cfg}, nil
+}
+
+// Dir returns the directory the Manager installs into
+func (m *Manager) Dir() string {
+	return m.config.Dir
+}
+
+// List returns the installed packs, including script packs, sorted by
+// language
+func (m *Manager) List() ([]Pack, error) {
+	var packs []Pack
+	for _, pattern := range []string{"*" + Ext, filepath.Join("script", "*"+Ext)} {
+		matches, err := filepath.Glob(filepath.Join(m.config.Dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			fi, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			rel, _ := filepath.Rel(m.config.Dir, path)
+			packs = append(packs, Pack{
+				Lang:    strings.TrimSuffix(filepath.ToSlash(rel), Ext),
+				Path:    path,
+				Size:    fi.Size(),
+				ModTime: fi.ModTime(),
+			})
+		}
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Lang < packs[j].Lang })
+	return packs, nil
+}
+
+// Install downloads the packs of langs, replacing installed versions.
+// Each element may be a "+"-joined spec such as "eng+script/Latin". A file
+// is only moved into place after it matched its checksum, so tesseract
+// never sees a partial or corrupt pack.
+func (m *Manager) Install(ctx context.Context, langs ...string) error {
+	packs, err := parse(langs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lang := range packs {
+		if err := m.install(ctx, lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// install downloads one pack. The source is only opened once the pack can
+// be verified, so nothing is fetched for a pack that would be rejected.
+func (m *Manager) install(ctx context.Context, lang string) error {
+	want, err := m.checksum(ctx, lang)
+	if err != nil {
+		return err
+	}
+	if want == "" && !m.config.AllowUnverified {
+		return fmt.Errorf("%w: %s", ErrNoChecksum, lang)
+	}
+
+	src, err := m.config.Source.Open(ctx, m.config.Variant, lang)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst := m.path(lang)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	// The temporary file lives next to dst so the rename is atomic
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("tessdata: downloading %s: %w", lang, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); want != "" && got != want {
+		return fmt.Errorf("%w: %s is %s, want %s", ErrChecksumMismatch, lang, got, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Remove deletes the installed packs of langs
+func (m *Manager) Remove(langs ...string) error {
+	packs, err := parse(langs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lang := range packs {
+		if err := os.Remove(m.path(lang)); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("%w: %s", ErrNotInstalled, lang)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks the installed packs of langs, or all installed packs if
+// langs is empty, against their checksums. The error joins one error per
+// failing pack: ErrNotInstalled, ErrNoChecksum or ErrChecksumMismatch.
+func (m *Manager) Verify(ctx context.Context, langs ...string) error {
+	packs, err := parse(langs)
+	if err != nil {
+		return err
+	}
+	if len(langs) == 0 {
+		installed, err := m.List()
+		if err != nil {
+			return err
+		}
+		for _, p := range installed {
+			packs = append(packs, p.Lang)
+		}
+	}
+
+	var errs []error
+	for _, lang := range packs {
+		if err := m.verify(ctx, lang); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) verify(ctx context.Context, lang string) error {
+	m.mu.Lock()
+	want, err := m.checksum(ctx, lang)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	got, err := fileChecksum(m.path(lang))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotInstalled, lang)
+	}
+	if err != nil {
+		return err
+	}
+	if want == "" {
+		return fmt.Errorf("%w: %s", ErrNoChecksum, lang)
+	}
+	if got != want {
+		return fmt.Errorf("%w: %s is %s, want %s", ErrChecksumMismatch, lang, got, want)
+	}
+	return nil
+}
+
+// checksum returns the expected sum of lang, or "" if none is known. The
+// source's sums are fetched once; m.mu must be held.
+func (m *Manager) checksum(ctx context.Context, lang string) (string, error) {
+	if sum, ok := m.config.Checksums[lang]; ok {
+		return sum, nil
+	}
+	if m.sums == nil {
+		sums, err := m.config.Source.Checksums(ctx, m.config.Variant)
+		if err != nil {
+			return "", fmt.Errorf("tessdata: fetching checksums: %w", err)
+		}
+		if sums == nil {
+			sums = map[string]string{}
+		}
+		m.sums = sums
+	}
+	return m.sums[lang], nil
+}
+
+// path returns the installed location of lang
+func (m *Manager) path(lang string) string {
+	return filepath.Join(m.config.Dir, filepath.FromSlash(lang)+Ext)
+}
+
+// parse splits language specs into single packs. Excluded ("~")
+// components name no pack and are rejected.
+func parse(langs []string) ([]string, error) {
+	var packs []string
+	for _, spec := range langs {
+		parsed, err := tesseract.ParseLanguages(spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, lang := range parsed {
+			if strings.HasPrefix(lang, "~") {
+				return nil, fmt.Errorf("%w: cannot install excluded language %q", tesseract.ErrInvalidConfig, lang)
+			}
+			packs = append(packs, lang)
+		}
+	}
+	return packs, nil
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,227 @@
+package tessdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mirror writes a Dir source holding the given packs of variant, with a
+// SHA256SUMS file listing them
+func mirror(t *testing.T, variant Variant, packs map[string]string) Dir {
+	t.Helper()
+	root := t.TempDir()
+	repo := filepath.Join(root, variant.Repository())
+
+	var sums strings.Builder
+	for lang, content := range packs {
+		path := filepath.Join(repo, filepath.FromSlash(lang)+Ext)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&sums, "%s  %s%s\n", sha(content), lang, Ext)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ChecksumFile), []byte(sums.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return Dir(root)
+}
+
+func sha(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func newManager(t *testing.T, cfg Config) *Manager {
+	t.Helper()
+	if cfg.Dir == "" {
+		cfg.Dir = t.TempDir()
+	}
+	m, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	return m
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	src := mirror(t, Best, map[string]string{"eng": "english", "fra": "french", "script/Latin": "latin"})
+	m := newManager(t, Config{Source: src, Variant: Best})
+
+	if err := m.Install(ctx, "eng+script/Latin", "fra"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(m.Dir(), "script", "Latin"+Ext))
+	if err != nil || string(data) != "latin" {
+		t.Errorf("script/Latin%s = %q, %v", Ext, data, err)
+	}
+
+	packs, err := m.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var langs []string
+	for _, p := range packs {
+		langs = append(langs, p.Lang)
+	}
+	if got := strings.Join(langs, " "); got != "eng fra script/Latin" {
+		t.Errorf("List() = %s, want eng fra script/Latin", got)
+	}
+
+	if err := m.Verify(ctx); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir(), "fra"+Ext), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Verify(ctx); !errors.Is(err, ErrChecksumMismatch) || !strings.Contains(err.Error(), "fra") {
+		t.Errorf("Verify() after tampering error = %v, want %v for fra", err, ErrChecksumMismatch)
+	}
+
+	if err := m.Remove("fra"); err != nil {
+		t.Errorf("Remove() error = %v", err)
+	}
+	if err := m.Remove("fra"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Remove() twice error = %v, want %v", err, ErrNotInstalled)
+	}
+	if err := m.Verify(ctx, "fra"); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Verify(fra) after Remove error = %v, want %v", err, ErrNotInstalled)
+	}
+
+	if err := m.Install(ctx, "deu"); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Install(deu) error = %v, want %v", err, ErrNoChecksum)
+	}
+	withSum := newManager(t, Config{Source: src, Variant: Best, Checksums: map[string]string{"deu": sha("german")}})
+	if err := withSum.Install(ctx, "deu"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Install(deu) with a checksum error = %v, want %v", err, ErrNotFound)
+	}
+	if err := m.Install(ctx, "eng+~fra"); err == nil {
+		t.Error("Install(eng+~fra) error = nil, want an error")
+	}
+}
+
+func TestInstallChecksumMismatch(t *testing.T) {
+	src := mirror(t, Fast, map[string]string{"eng": "new english"})
+	m := newManager(t, Config{Source: src, Checksums: map[string]string{"eng": sha("old english")}})
+
+	path := filepath.Join(m.Dir(), "eng"+Ext)
+	if err := os.WriteFile(path, []byte("old english"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Install(context.Background(), "eng"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Install() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+
+	// The installed file is untouched and no temporary file is left
+	entries, err := os.ReadDir(m.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("target directory holds %d entries, want 1", len(entries))
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old english" {
+		t.Errorf("eng%s = %q after failed install, want the old file", Ext, data)
+	}
+}
+
+// countingSource counts the packs opened from a Source
+type countingSource struct {
+	Source
+	opened int
+}
+
+func (s *countingSource) Open(ctx context.Context, variant Variant, lang string) (io.ReadCloser, error) {
+	s.opened++
+	return s.Source.Open(ctx, variant, lang)
+}
+
+func TestInstallUnverified(t *testing.T) {
+	src := Dir(t.TempDir())
+	path := filepath.Join(string(src), Legacy.Repository(), "eng"+Ext)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("english"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	counting := &countingSource{Source: src}
+	m := newManager(t, Config{Source: counting, Variant: Legacy})
+	if err := m.Install(context.Background(), "eng"); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Install() without checksum error = %v, want %v", err, ErrNoChecksum)
+	}
+	if counting.opened != 0 {
+		t.Errorf("Install() without checksum opened the source %d times, want 0", counting.opened)
+	}
+
+	m = newManager(t, Config{Source: src, Variant: Legacy, AllowUnverified: true})
+	if err := m.Install(context.Background(), "eng"); err != nil {
+		t.Errorf("Install() with AllowUnverified error = %v", err)
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	src := mirror(t, Fast, map[string]string{"eng": "english"})
+	srv := httptest.NewServer(http.StripPrefix("/mirror", http.FileServer(http.Dir(src))))
+	defer srv.Close()
+
+	m := newManager(t, Config{Source: &HTTP{BaseURL: srv.URL + "/mirror/", Client: srv.Client()}})
+	if err := m.Install(context.Background(), "eng"); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := m.Verify(context.Background(), "eng"); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := m.Install(context.Background(), "fra"); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Install(fra) error = %v, want %v", err, ErrNoChecksum)
+	}
+	m = newManager(t, Config{Source: &HTTP{BaseURL: srv.URL + "/mirror/", Client: srv.Client()}, AllowUnverified: true})
+	if err := m.Install(context.Background(), "fra"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Install(fra) with AllowUnverified error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	input := "# generated\n" +
+		sum + "  eng.traineddata\n" +
+		strings.ToUpper(sum) + " *script/Latin.traineddata\n" +
+		sum + "  README.md\n"
+
+	sums, err := ParseChecksums(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseChecksums() error = %v", err)
+	}
+	if len(sums) != 2 || sums["eng"] != sum || sums["script/Latin"] != sum {
+		t.Errorf("ParseChecksums() = %v", sums)
+	}
+
+	for _, bad := range []string{"deadbeef", strings.Repeat("zz", 32), strings.Repeat("ab", 33)} {
+		if _, err := ParseChecksums(strings.NewReader(bad + "  eng.traineddata\n")); err == nil {
+			t.Errorf("ParseChecksums() with sum %q error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestNewManagerChecksums(t *testing.T) {
+	sum := strings.Repeat("zz", 32)
+	_, err := NewManager(Config{Dir: t.TempDir(), Checksums: map[string]string{"deu": sum}})
+	if err == nil || !strings.Contains(err.Error(), "deu") {
+		t.Errorf("NewManager() with checksum %q error = %v, want one naming deu", sum, err)
+	}
+}
@@ -0,0 +1,63 @@
+package tesseracttest
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// fakePackage is the import path of the fake tesseract command
+const fakePackage = "github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest/faketesseract"
+
+// Build compiles a fake tesseract binary into dir and returns its path.
+// The binary answers from the files in fixtures:
+//
+//	version.txt        output of --version (default "tesseract 5.3.0")
+//	langs.txt          languages for --list-langs (default "eng osd"),
+//	                   unless --tessdata-dir names a directory, whose
+//	                   traineddata files are listed instead
+//	output.<suffix>    rendered output, e.g. output.txt, output.hocr,
+//	                   output.tsv, output.pdf, output.xml, output.box
+//	osd.txt            output of --psm 0
+//	stderr.txt         written to stderr on every recognition run
+//	delay.txt          a duration, e.g. "10s", to wait before recognising
+//	                   each page
+//
+// Unknown languages and missing images fail like the real binary does;
+// languages are loaded before the image is read. With -c stream_filelist=1
+// the binary reads image paths from stdin and writes each page's text as
+// it arrives; see faketesseract's streamPages. TESSERACTTEST_FIXTURES,
+// if set when the binary runs, names another fixture directory. Setting
+// TESSERACTTEST_LOG makes the binary append each invocation's arguments to
+// that file, tab separated, followed by NAME=value for every variable named
+// in the comma-separated TESSERACTTEST_LOG_ENV. Build is meant to be called
+// from TestMain and needs the go command.
+func Build(dir, fixtures string) (string, error) {
+	fixtures, err := filepath.Abs(fixtures)
+	if err != nil {
+		return "", err
+	}
+
+	name := "tesseract"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	out := filepath.Join(dir, name)
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		goTool = filepath.Join(runtime.GOROOT(), "bin", "go")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(goTool, "build", "-o", out,
+		"-ldflags", fmt.Sprintf("-X 'main.fixtureDir=%s'", fixtures),
+		fakePackage)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseracttest: building fake tesseract: %v\n%s", err, stderr.String())
+	}
+	return out, nil
+}
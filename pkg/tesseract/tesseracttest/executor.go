@@ -0,0 +1,143 @@
+// Package tesseracttest provides test doubles for code that uses the
+// tesseract package without a Tesseract installation
+package tesseracttest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+)
+
+// Call records a single Executor.Run invocation
+type Call struct {
+	Args  []string
+	Stdin []byte
+}
+
+// response is a recorded execution, or a function producing one, and the
+// arguments it answers
+type response struct {
+	match []string
+	exec  tesseract.Execution
+	fn    func(Call) tesseract.Execution
+}
+
+// Executor is an in-memory tesseract.Executor that answers --version and
+// --list-langs itself and replays recorded executions for everything else
+type Executor struct {
+	// Version is reported by --version, e.g. "5.3.0"
+	Version string
+
+	// Languages are reported by --list-langs
+	Languages []string
+
+	mu        sync.Mutex
+	responses []response
+	calls     []Call
+}
+
+// NewExecutor returns an Executor reporting the given version and languages
+func NewExecutor(version string, languages ...string) *Executor {
+	return &Executor{Version: version, Languages: languages}
+}
+
+// Respond records exec as the answer to runs whose arguments include every
+// string in match. Responses are tried in the order they were recorded; a
+// response with no match strings answers any run.
+func (e *Executor) Respond(exec tesseract.Execution, match ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses = append(e.responses, response{match: match, exec: exec})
+}
+
+// RespondFunc is like Respond but answers with the result of fn, which
+// runs while the input files named in the call's arguments, such as an
+// image list, still exist. fn may be called concurrently.
+func (e *Executor) RespondFunc(fn func(Call) tesseract.Execution, match ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses = append(e.responses, response{match: match, fn: fn})
+}
+
+// Calls returns the runs seen so far, including --version and --list-langs
+func (e *Executor) Calls() []Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.calls)
+}
+
+// Run implements tesseract.Executor
+func (e *Executor) Run(ctx context.Context, args []string, stdin io.Reader) (*tesseract.Execution, error) {
+	call := Call{Args: slices.Clone(args)}
+	if stdin != nil {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, err
+		}
+		call.Stdin = data
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r, ok := e.record(call)
+	switch {
+	case slices.Contains(args, "--version"):
+		return &tesseract.Execution{Stdout: []byte("tesseract " + e.Version + "\n")}, nil
+	case slices.Contains(args, "--list-langs"):
+		out := fmt.Sprintf("List of available languages (%d):\n%s\n", len(e.Languages), strings.Join(e.Languages, "\n"))
+		return &tesseract.Execution{Stdout: []byte(out)}, nil
+	case ok && r.fn != nil:
+		return clone(r.fn(call)), nil
+	case ok:
+		return clone(r.exec), nil
+	}
+	return &tesseract.Execution{
+		ExitCode: 1,
+		Stderr:   []byte(fmt.Sprintf("tesseracttest: no recorded response for %q\n", args)),
+	}, nil
+}
+
+// record adds call to the log and returns the response answering it
+func (e *Executor) record(call Call) (response, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, call)
+	for _, r := range e.responses {
+		if matches(call.Args, r.match) {
+			return r, true
+		}
+	}
+	return response{}, false
+}
+
+func matches(args, match []string) bool {
+	for _, m := range match {
+		if !slices.Contains(args, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// clone copies exec so callers cannot modify the recorded response
+func clone(exec tesseract.Execution) *tesseract.Execution {
+	out := &tesseract.Execution{
+		Stdout:      slices.Clone(exec.Stdout),
+		Stderr:      slices.Clone(exec.Stderr),
+		ExitCode:    exec.ExitCode,
+		CommandLine: slices.Clone(exec.CommandLine),
+	}
+	if exec.Files != nil {
+		out.Files = make(map[string][]byte, len(exec.Files))
+		for name, data := range exec.Files {
+			out.Files[name] = slices.Clone(data)
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package tesseracttest_test
+
+import (
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestExecutor(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0", "eng", "osd")
+	exec.Respond(tesseract.Execution{
+		Files: map[string][]byte{"box": []byte("H 1 2 3 4 0\n")},
+	}, "makebox")
+	cmdline := []string{"nice", "-n", "10", "tesseract", "-", "stdout"}
+	exec.Respond(tesseract.Execution{Stdout: []byte("Hello\n"), CommandLine: cmdline}, "stdout")
+
+	client, err := tesseract.NewClient(tesseract.Config{Executor: exec, Language: "eng"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+
+	var info tesseract.RunInfo
+	text, err := client.ImageToString(img, "", tesseract.Options{Info: &info})
+	if err != nil || text != "Hello\n" {
+		t.Errorf("ImageToString() = %q, %v; want %q", text, err, "Hello\n")
+	}
+	if !reflect.DeepEqual(info.CommandLine, cmdline) {
+		t.Errorf("RunInfo.CommandLine = %q, want the recorded %q", info.CommandLine, cmdline)
+	}
+
+	boxes, err := client.ImageToBoxes(img, "")
+	want := []tesseract.Box{{Char: 'H', Left: 1, Bottom: 2, Right: 3, Top: 4}}
+	if err != nil || !reflect.DeepEqual(boxes, want) {
+		t.Errorf("ImageToBoxes() = %+v, %v; want %+v", boxes, err, want)
+	}
+
+	langs, err := client.GetAvailableLanguages()
+	if err != nil || !reflect.DeepEqual(langs, []string{"eng", "osd"}) {
+		t.Errorf("GetAvailableLanguages() = %q, %v", langs, err)
+	}
+
+	// The language list is fetched for ImageToBoxes and then cached
+	var listed int
+	for _, call := range exec.Calls() {
+		if reflect.DeepEqual(call.Args, []string{"--list-langs"}) {
+			listed++
+		}
+	}
+	if listed != 1 {
+		t.Errorf("--list-langs ran %d times, want 1", listed)
+	}
+}
+
+func TestExecutorImageToFile(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.Respond(tesseract.Execution{Files: map[string][]byte{"txt": []byte("Hello\n")}})
+
+	client, err := tesseract.NewClient(tesseract.Config{Executor: exec})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "result")
+	if err := client.ImageToFile(image.NewGray(image.Rect(0, 0, 8, 8)), "", out); err != nil {
+		t.Fatalf("ImageToFile() error = %v", err)
+	}
+	data, err := os.ReadFile(out + ".txt")
+	if err != nil || string(data) != "Hello\n" {
+		t.Errorf("output file = %q, %v; want %q", data, err, "Hello\n")
+	}
+}
+
+func TestExecutorNoResponse(t *testing.T) {
+	client, err := tesseract.NewClient(tesseract.Config{Executor: tesseracttest.NewExecutor("5.3.0")})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, err = client.ImageToString(image.NewGray(image.Rect(0, 0, 8, 8)), "")
+	var ocrErr *tesseract.OCRError
+	if !errors.As(err, &ocrErr) || ocrErr.Code != 1 {
+		t.Errorf("ImageToString() error = %v, want OCRError with code 1", err)
+	}
+}
+
+func TestExecutorRespondFunc(t *testing.T) {
+	exec := tesseracttest.NewExecutor("5.3.0")
+	exec.RespondFunc(func(call tesseracttest.Call) tesseract.Execution {
+		data, err := os.ReadFile(call.Args[0])
+		if err != nil {
+			return tesseract.Execution{ExitCode: 1, Stderr: []byte(err.Error())}
+		}
+		return tesseract.Execution{Stdout: data}
+	})
+
+	client, err := tesseract.NewClient(tesseract.Config{Executor: exec})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// The input file only exists while the run is in progress
+	text, err := client.InputToString(tesseract.BytesInput([]byte("GIF89a"), ""), "")
+	if err != nil || text != "GIF89a" {
+		t.Errorf("InputToString() = %q, %v; want the input file's content", text, err)
+	}
+}
@@ -0,0 +1,338 @@
+// Command faketesseract imitates the tesseract command line for tests. It
+// answers from a fixture directory, set at link time through main.fixtureDir
+// or at run time through TESSERACTTEST_FIXTURES; see tesseracttest.Build.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fixtureDir is set with -ldflags "-X main.fixtureDir=..."
+var fixtureDir string
+
+// tessdataDir is the --tessdata-dir argument; when set, languages are the
+// traineddata files found there instead of langs.txt
+var tessdataDir string
+
+// renderers maps config file names and tessedit_create_* variables to the
+// suffix of the fixture they produce
+var renderers = map[string]string{
+	"txt":        "txt",
+	"hocr":       "hocr",
+	"tsv":        "tsv",
+	"pdf":        "pdf",
+	"alto":       "xml",
+	"makebox":    "box",
+	"lstmbox":    "box",
+	"wordstrbox": "box",
+}
+
+var createVariables = map[string]string{
+	"tessedit_create_txt":     "txt",
+	"tessedit_create_hocr":    "hocr",
+	"tessedit_create_tsv":     "tsv",
+	"tessedit_create_pdf":     "pdf",
+	"tessedit_create_alto":    "xml",
+	"tessedit_create_boxfile": "box",
+}
+
+func main() {
+	if dir := os.Getenv("TESSERACTTEST_FIXTURES"); dir != "" {
+		fixtureDir = dir
+	}
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	logCall(args)
+
+	for i, a := range args {
+		if a == "--tessdata-dir" && i+1 < len(args) {
+			tessdataDir = filepath.Clean(args[i+1])
+		}
+	}
+
+	for _, a := range args {
+		switch a {
+		case "--version", "-v":
+			return printFixture("version.txt", "tesseract 5.3.0\n")
+		case "--list-langs":
+			langs, dir := languages(), fixtureDir
+			if tessdataDir != "" {
+				dir = tessdataDir
+			}
+			// Tesseract 3 does not name the directory
+			if majorVersion() < 4 {
+				fmt.Printf("List of available languages (%d):\n", len(langs))
+			} else {
+				fmt.Printf("List of available languages in \"%s/\" (%d):\n", dir, len(langs))
+			}
+			for _, l := range langs {
+				fmt.Println(l)
+			}
+			return 0
+		}
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: tesseract imagename outputbase [options...] [configfile...]")
+		return 1
+	}
+	image, outBase := args[0], args[1]
+
+	lang, psm := "eng", ""
+	stream, separator := false, "\f"
+	outputs := make(map[string]bool)
+	for i := 2; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "-l" && i+1 < len(args):
+			i++
+			lang = args[i]
+		case a == "--psm" && i+1 < len(args):
+			i++
+			psm = args[i]
+		case a == "-c" && i+1 < len(args):
+			i++
+			name, value, _ := strings.Cut(args[i], "=")
+			if suffix, ok := createVariables[name]; ok && value == "1" {
+				outputs[suffix] = true
+			}
+			switch name {
+			case "stream_filelist":
+				stream = value == "1" || value == "true"
+			case "page_separator":
+				separator = value
+			}
+		case strings.HasPrefix(a, "-") && i+1 < len(args):
+			i++
+		default:
+			if suffix, ok := renderers[a]; ok {
+				outputs[suffix] = true
+			}
+		}
+	}
+
+	// Like tesseract, load the languages before reading the image
+	if psm == "0" {
+		lang = "osd"
+	}
+	for _, l := range strings.Split(lang, "+") {
+		if !hasLanguage(l) {
+			fmt.Fprintf(os.Stderr, "Error opening data file %s/%s.traineddata\n", fixtureDir, l)
+			fmt.Fprintf(os.Stderr, "Failed loading language '%s'\n", l)
+			fmt.Fprintln(os.Stderr, "Tesseract couldn't load any languages!")
+			fmt.Fprintln(os.Stderr, "Could not initialize tesseract.")
+			return 1
+		}
+	}
+
+	if stream {
+		return streamPages(separator)
+	}
+
+	if image != "stdin" && image != "-" {
+		if _, err := os.Stat(image); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in pixRead: image file not found: %s\n", image)
+			fmt.Fprintf(os.Stderr, "Image file %s cannot be read!\n", image)
+			fmt.Fprintln(os.Stderr, "Error during processing.")
+			return 1
+		}
+	} else {
+		// Drain the image like tesseract does before recognising it
+		if _, err := io.Copy(io.Discard, os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "faketesseract: reading stdin: %v\n", err)
+			return 1
+		}
+	}
+
+	if err := startPage(); err != nil {
+		fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+		return 1
+	}
+
+	if psm == "0" {
+		return printFixture("osd.txt", "")
+	}
+
+	if len(outputs) == 0 {
+		outputs["txt"] = true
+	}
+	for suffix := range outputs {
+		data, err := os.ReadFile(filepath.Join(fixtureDir, "output."+suffix))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+			return 1
+		}
+		if outBase == "stdout" || outBase == "-" {
+			os.Stdout.Write(data)
+			continue
+		}
+		if err := os.WriteFile(outBase+"."+suffix, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// startPage waits for delay.txt and writes stderr.txt, as every
+// recognised page does
+func startPage() error {
+	if data, err := os.ReadFile(filepath.Join(fixtureDir, "delay.txt")); err == nil {
+		d, err := time.ParseDuration(strings.TrimSpace(string(data)))
+		if err != nil {
+			return fmt.Errorf("delay.txt: %w", err)
+		}
+		time.Sleep(d)
+	}
+	if data, err := os.ReadFile(filepath.Join(fixtureDir, "stderr.txt")); err == nil {
+		os.Stderr.Write(data)
+	}
+	return nil
+}
+
+// streamPages recognises the images named on stdin, one per line, as they
+// arrive, like tesseract with stream_filelist set. Each page's text is
+// output.txt without its page separator, with {width} and {height}
+// replaced by the image's size. An image that does not decode ends the
+// run like pixRead failing does. Pages are separated the way the release
+// in version.txt does: tesseract 5 writes the separator between pages,
+// older releases after every page.
+func streamPages(separator string) int {
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "output.txt"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+		return 1
+	}
+	text := strings.TrimSuffix(string(data), "\f")
+	after := majorVersion() < 5
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for page := 0; scanner.Scan(); page++ {
+		path := scanner.Text()
+		cfg, err := decodeConfig(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error in pixReadStream: Unknown format: no pix returned")
+			fmt.Fprintf(os.Stderr, "Image file %s cannot be read!\n", path)
+			return 1
+		}
+		if err := startPage(); err != nil {
+			fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+			return 1
+		}
+
+		out := strings.NewReplacer("{width}", strconv.Itoa(cfg.Width), "{height}", strconv.Itoa(cfg.Height)).Replace(text)
+		if !after && page > 0 {
+			out = separator + out
+		}
+		if after {
+			out += separator
+		}
+		os.Stdout.WriteString(out)
+	}
+	return 0
+}
+
+func decodeConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	return cfg, err
+}
+
+// majorVersion returns the major release in version.txt, defaulting to 5
+func majorVersion() int {
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "version.txt"))
+	if err != nil {
+		return 5
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 5
+	}
+	major, err := strconv.Atoi(strings.SplitN(strings.TrimPrefix(fields[1], "v"), ".", 2)[0])
+	if err != nil {
+		return 5
+	}
+	return major
+}
+
+// printFixture copies a fixture file to stdout, or prints fallback if the
+// fixture does not exist and fallback is not empty
+func printFixture(name, fallback string) int {
+	data, err := os.ReadFile(filepath.Join(fixtureDir, name))
+	if err != nil {
+		if fallback == "" {
+			fmt.Fprintf(os.Stderr, "faketesseract: %v\n", err)
+			return 1
+		}
+		data = []byte(fallback)
+	}
+	os.Stdout.Write(data)
+	return 0
+}
+
+// languages reads langs.txt, defaulting to eng and osd, or lists the
+// traineddata files in tessdataDir and its script subdirectory
+func languages() []string {
+	if tessdataDir != "" {
+		var langs []string
+		for _, pattern := range []string{"*.traineddata", "script/*.traineddata"} {
+			matches, _ := filepath.Glob(filepath.Join(tessdataDir, pattern))
+			for _, m := range matches {
+				rel, _ := filepath.Rel(tessdataDir, m)
+				langs = append(langs, strings.TrimSuffix(filepath.ToSlash(rel), ".traineddata"))
+			}
+		}
+		return langs
+	}
+	data, err := os.ReadFile(filepath.Join(fixtureDir, "langs.txt"))
+	if err != nil {
+		return []string{"eng", "osd"}
+	}
+	return strings.Fields(string(data))
+}
+
+func hasLanguage(lang string) bool {
+	for _, l := range languages() {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// logCall appends args as a tab-separated line to TESSERACTTEST_LOG,
+// followed by NAME=value for each variable TESSERACTTEST_LOG_ENV names
+func logCall(args []string) {
+	path := os.Getenv("TESSERACTTEST_LOG")
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fields := args
+	if names := os.Getenv("TESSERACTTEST_LOG_ENV"); names != "" {
+		for _, name := range strings.Split(names, ",") {
+			fields = append(fields, name+"="+os.Getenv(name))
+		}
+	}
+	fmt.Fprintln(f, strings.Join(fields, "\t"))
+}
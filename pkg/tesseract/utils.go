@@ -7,39 +7,37 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
-var versionRegex = regexp.MustCompile(`tesseract\s+v?(\d+)\.(\d+)\.(\d+)(?:\.\d+)?`)
-
-func checkVersion() error {
-	cmd := exec.Command(TesseractCmd, "--version")
-	out, err := cmd.CombinedOutput()
+func (c *Client) checkVersion(ctx context.Context, b *binary) (VersionInfo, error) {
+	res, err := c.executor(b).Run(ctx, []string{"--version"}, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get tesseract version: %w", err)
+		return VersionInfo{}, fmt.Errorf("failed to get tesseract version: %w", notFoundError(err))
 	}
-
-	// Extract first line which contains version
-	firstLine := strings.SplitN(string(out), "\n", 2)[0]
-	matches := versionRegex.FindStringSubmatch(firstLine)
-	if matches == nil {
-		return fmt.Errorf("unrecognized tesseract version format: %s", firstLine)
+	if res.ExitCode != 0 {
+		return VersionInfo{}, fmt.Errorf("failed to get tesseract version: %w", newOCRError(res.ExitCode, res.Stderr))
 	}
 
-	// Parse version numbers
-	major := matches[1]
-	minor := matches[2]
+	// Tesseract 3 prints the version to stderr, later releases to stdout
+	info, err := parseVersionInfo(string(res.Stdout) + string(res.Stderr))
+	if err != nil {
+		return VersionInfo{}, err
+	}
 
 	// Check minimum version requirement
-	if major < "3" || (major == "3" && minor < "05") {
-		return fmt.Errorf("tesseract version %s.%s is not supported (minimum 3.05 required)", major, minor)
+	if v := info.Tesseract; !v.AtLeast(minVersion) {
+		return VersionInfo{}, fmt.Errorf("tesseract version %d.%02d is not supported (minimum 3.05 required)", v.Major, v.Minor)
 	}
 
-	return nil
+	return info, nil
 }
 
 func createTempDir() (string, error) {
@@ -50,78 +48,306 @@ func createTempDir() (string, error) {
 	return dir, nil
 }
 
-func saveImage(dir string, img image.Image) (string, error) {
-	outPath := filepath.Join(dir, "input.png")
-	f, err := os.Create(outPath)
+// saveImage writes img to path as PNG
+func saveImage(path string, img image.Image) error {
+	f, err := os.Create(path)
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer f.Close()
 
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		return "", err
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
 	}
+	return f.Close()
+}
 
-	if _, err := f.Write(buf.Bytes()); err != nil {
+// GetAvailableLanguages lists the languages installed for the default client
+func GetAvailableLanguages() ([]string, error) {
+	return DefaultClient.GetAvailableLanguages()
+}
+
+// GetAvailableLanguages lists the languages tesseract reports as installed.
+// The list is fetched once per binary and then served from memory until
+// the tessdata directory changes or RefreshLanguages is called.
+func (c *Client) GetAvailableLanguages() ([]string, error) {
+	return c.languages(false)
+}
+
+// RefreshLanguages discards the cached language list and asks tesseract
+// again, e.g. after traineddata files were installed
+func (c *Client) RefreshLanguages() ([]string, error) {
+	return c.languages(true)
+}
+
+// TessdataDir returns the tessdata directory tesseract reads languages
+// from, as printed by --list-langs. Tesseract 3 does not print it; the
+// result is then Config.TessdataDir, which may be empty.
+func (c *Client) TessdataDir() (string, error) {
+	b := c.binary()
+	if _, err := c.languages(false); err != nil {
 		return "", err
 	}
+	b.langsMu.Lock()
+	defer b.langsMu.Unlock()
+	if b.tessdata == "" {
+		return c.config.TessdataDir, nil
+	}
+	return b.tessdata, nil
+}
+
+// languages returns the cached language list, listing it again when
+// refresh is set, nothing is cached yet or the tessdata directory was
+// modified since it was listed. The directory is the one --list-langs
+// named or, on Tesseract 3, Config.TessdataDir; with neither, only
+// RefreshLanguages notices new languages.
+func (c *Client) languages(refresh bool) ([]string, error) {
+	b := c.binary()
+	b.langsMu.Lock()
+	defer b.langsMu.Unlock()
+	stampDir := func() string {
+		if b.tessdata != "" {
+			return b.tessdata
+		}
+		return c.config.TessdataDir
+	}
+	if refresh || b.langs == nil ||
+		(stampDir() != "" && !tessdataStamp(stampDir()).Equal(b.tessdataStamp)) {
+		langs, dir, err := c.listLanguages(b)
+		if err != nil {
+			return nil, err
+		}
+		b.langs, b.tessdata = langs, dir
+		if dir := stampDir(); dir != "" {
+			b.tessdataStamp = tessdataStamp(dir)
+		}
+	}
+	return append([]string(nil), b.langs...), nil
+}
 
-	return outPath, nil
+// tessdataStamp returns the latest modification time of dir and its
+// script pack subdirectory, or the zero time if dir is missing. Adding or
+// removing a traineddata file changes it.
+func tessdataStamp(dir string) time.Time {
+	var stamp time.Time
+	for _, d := range []string{dir, filepath.Join(dir, "script")} {
+		if fi, err := os.Stat(d); err == nil && fi.ModTime().After(stamp) {
+			stamp = fi.ModTime()
+		}
+	}
+	return stamp
 }
 
-func GetAvailableLanguages() ([]string, error) {
-	cmd := exec.Command(TesseractCmd, "--list-langs")
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, ErrTesseractNotFound
+// listHeaderRegex matches the first line of --list-langs output on
+// Tesseract 4 and later, which names the tessdata directory
+var listHeaderRegex = regexp.MustCompile(`^List of available languages in "(.*)" \(\d+\):`)
+
+// listLanguages runs tesseract --list-langs and returns the languages and
+// the tessdata directory named in the header
+func (c *Client) listLanguages(b *binary) ([]string, string, error) {
+	args := []string{"--list-langs"}
+	if c.config.TessdataDir != "" {
+		args = []string{"--tessdata-dir", c.config.TessdataDir, "--list-langs"}
+	}
+	res, err := c.executor(b).Run(context.Background(), args, nil)
+	if err != nil || res.ExitCode != 0 {
+		return nil, "", ErrTesseractNotFound
 	}
+	out := res.Stdout
 	// this based on the os for windows it is \r\n and for linux it is \n
 	if strings.Contains(string(out), "\r\n") {
 		out = bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n"))
 	}
 	langs := strings.Split(string(out), "\n")
-	var langsOutput []string
+	langsOutput := []string{}
+	var dir string
 
 	for i, l := range langs {
 		if i == 0 {
+			if m := listHeaderRegex.FindStringSubmatch(l); m != nil && m[1] != "" {
+				dir = filepath.Clean(m[1])
+			}
 			continue
 		}
 		if len(l) > 0 {
 			langsOutput = append(langsOutput, l)
 		}
 	}
-	return langsOutput, nil
+	return langsOutput, dir, nil
+}
+
+// ocrRequest describes the arguments of a single tesseract recognition run
+type ocrRequest struct {
+	// outBase is "stdout", or empty to have runOCR pick an output base in
+	// its temporary directory and return the rendered files
+	outBase string
+
+	// lang is the resolved -l value, empty for tesseract's default
+	lang string
+
+	// options are flags such as --psm; they must precede configs
+	options []string
+
+	// variables are passed as "-c name=value" flags
+	variables map[string]string
+
+	// configs are tesseract config file names, applied in order
+	configs []string
+
+	// info receives the details of the run, if set
+	info *RunInfo
+}
+
+// args builds the tesseract command line for imgPath. Tesseract treats
+// every argument after the first non-flag following the output base as a
+// config file, so all flags are placed before the config names.
+func (r ocrRequest) args(imgPath string) []string {
+	args := []string{imgPath, r.outBase}
+	if r.lang != "" {
+		args = append(args, "-l", r.lang)
+	}
+	args = append(args, r.options...)
+
+	names := make([]string, 0, len(r.variables))
+	for name := range r.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "-c", name+"="+r.variables[name])
+	}
+
+	return append(args, r.configs...)
+}
+
+// require sets a variable the client depends on for this run. It fails
+// with ErrVariableConflict if the caller asked for a different value.
+func (r *ocrRequest) require(name, value string) error {
+	if current, ok := r.variables[name]; ok && current != value {
+		return fmt.Errorf("%w: %s=%q, but %q is required", ErrVariableConflict, name, current, value)
+	}
+	if r.variables == nil {
+		r.variables = make(map[string]string)
+	}
+	r.variables[name] = value
+	return nil
 }
 
-func runOCR(ctx context.Context, img image.Image, lang string, args []string) ([]byte, error) {
-	tmpDir, err := createTempDir()
+// requirePageBreaks makes tesseract end the text of every page with the
+// page separator. Tesseract 4.0 and newer always do; 3.x only does with
+// include_page_breaks set.
+func (c *Client) requirePageBreaks(req *ocrRequest) error {
+	info, err := c.tesseractVersion()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if info.Tesseract.AtLeast(Version{Major: 4}) {
+		return nil
 	}
-	defer os.RemoveAll(tmpDir)
+	return req.require("include_page_breaks", "1")
+}
 
-	imgPath, err := saveImage(tmpDir, img)
+// newRequest starts an ocrRequest for lang, falling back to the client's
+// default language, applying per-call opts and appending the client's
+// config file
+func (c *Client) newRequest(outBase, lang string, opts []Options) (ocrRequest, error) {
+	o := c.options(opts)
+	flags, err := c.flags(o)
 	if err != nil {
-		return nil, err
+		return ocrRequest{}, err
 	}
+	req := ocrRequest{outBase: outBase, lang: c.language(lang), options: flags, info: o.Info}
+	for name, value := range o.Variables {
+		if err := validateVariable(name); err != nil {
+			return ocrRequest{}, err
+		}
+		if req.variables == nil {
+			req.variables = make(map[string]string, len(o.Variables))
+		}
+		req.variables[name] = value
+	}
+	if c.config.ConfigFile != "" {
+		req.configs = append(req.configs, c.config.ConfigFile)
+	}
+	return req, nil
+}
 
-	cmdArgs := append([]string{imgPath}, args...)
-	if lang != "" {
-		cmdArgs = append(cmdArgs, "-l", lang)
+// language returns lang, or Config.Language when lang is empty
+func (c *Client) language(lang string) string {
+	if lang == "" {
+		return c.config.Language
 	}
+	return lang
+}
 
-	cmd := exec.CommandContext(ctx, TesseractCmd, cmdArgs...)
-	out, err := cmd.Output()
-	if err != nil {
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			return nil, &OCRError{Code: exitErr.ExitCode(), Stderr: string(exitErr.Stderr)}
+// runOCR runs one recognition after checking that req.lang is installed,
+// installing it first if Config.Installer is set. A temporary directory is
+// only created when the input or the outputs need files; see Input.source.
+func (c *Client) runOCR(ctx context.Context, in Input, req ocrRequest) (*Execution, error) {
+	if req.lang != "" {
+		if err := c.validateLanguage(ctx, req.lang); err != nil {
+			return nil, err
 		}
+	}
+
+	var tmpDir string
+	if in.needsFile(c.config.UseStdin) || req.outBase == "" {
+		var err error
+		if tmpDir, err = createTempDir(); err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmpDir)
+	}
+
+	input, stdin, closeInput, err := in.source(tmpDir, "input", c.config.UseStdin)
+	defer closeInput()
+	if err != nil {
 		return nil, err
 	}
+	if req.outBase == "" {
+		req.outBase = filepath.Join(tmpDir, "output")
+	}
+
+	b := c.binary()
+	args := req.args(input)
+	start := time.Now()
+	res, err := c.executor(b).Run(ctx, args, stdin)
+	if req.info != nil {
+		c.fillRunInfo(ctx, req.info, b, args, time.Since(start), res)
+	}
+	if ctxErr := contextError(ctx); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, notFoundError(err)
+	}
+	if res.ExitCode != 0 {
+		return nil, newOCRError(res.ExitCode, res.Stderr)
+	}
+
+	return res, nil
+}
 
-	return out, nil
+// notFoundError marks err with ErrTesseractNotFound if the binary could
+// not be started because it does not exist
+func notFoundError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrTesseractNotFound, err)
+	}
+	return err
+}
+
+// contextError reports why ctx ended, mapping an expired deadline to
+// ErrProcessTimeout. It returns nil while ctx is still live.
+func contextError(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return ErrProcessTimeout
+	default:
+		return ctx.Err()
+	}
 }
 
 func validateImageFormat(img image.Image) error {
@@ -131,15 +357,31 @@ func validateImageFormat(img image.Image) error {
 	return nil
 }
 
-func validateLanguage(lang string) error {
-	langs, err := GetAvailableLanguages()
-	if err != nil {
+// validateLanguage checks that every component of lang is installed. If
+// some are missing and Config.Installer is set, they are installed first.
+func (c *Client) validateLanguage(ctx context.Context, lang string) error {
+	err := c.CheckLanguages(lang)
+	var langErr *LanguageError
+	if c.config.Installer == nil || !errors.As(err, &langErr) {
 		return err
 	}
-	for _, l := range langs {
-		if l == lang {
-			return nil
-		}
+
+	b := c.binary()
+	b.installMu.Lock()
+	defer b.installMu.Unlock()
+
+	// Another run may have installed them while we waited
+	if _, err := c.RefreshLanguages(); err != nil {
+		return err
+	}
+	if err := c.CheckLanguages(lang); !errors.As(err, &langErr) {
+		return err
+	}
+	if err := c.config.Installer.Install(ctx, langErr.Missing...); err != nil {
+		return fmt.Errorf("installing %s: %w", strings.Join(langErr.Missing, "+"), err)
+	}
+	if _, err := c.RefreshLanguages(); err != nil {
+		return err
 	}
-	return ErrLanguageNotFound
+	return c.CheckLanguages(lang)
 }
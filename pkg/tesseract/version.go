@@ -0,0 +1,161 @@
+// Package tesseract provides Go bindings for the Tesseract OCR engine
+package tesseract
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	versionRegex   = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?`)
+	tesseractRegex = regexp.MustCompile(`tesseract\s+(v?\d+\.\d+\S*)`)
+	leptonicaRegex = regexp.MustCompile(`leptonica-(\S+)`)
+)
+
+// minVersion is the oldest tesseract release the package supports
+var minVersion = Version{Major: 3, Minor: 5}
+
+// Version is a tesseract (or library) release number. Tesseract 3
+// releases were numbered with two-digit minor versions, so "3.05.02"
+// parses as 3.5.2.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a version such as "5.3.0", "4.00.00alpha" or
+// "v5.0.0-alpha-20201231", ignoring anything after the patch number.
+func ParseVersion(s string) (Version, error) {
+	m := versionRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return sign(v.Major - o.Major)
+	case v.Minor != o.Minor:
+		return sign(v.Minor - o.Minor)
+	default:
+		return sign(v.Patch - o.Patch)
+	}
+}
+
+// AtLeast reports whether v is o or newer
+func (v Version) AtLeast(o Version) bool {
+	return v.Compare(o) >= 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// VersionInfo describes a tesseract binary as reported by --version
+type VersionInfo struct {
+	// Tesseract is the tesseract release
+	Tesseract Version
+
+	// Leptonica is the leptonica release tesseract is linked against, if
+	// reported
+	Leptonica Version
+
+	// Libraries maps the image and network libraries tesseract reports
+	// (libpng, libtiff, zlib, libcurl, ...) to their version strings
+	Libraries map[string]string
+
+	// Features lists the optional features reported as "Found ...", such
+	// as "AVX2" or "OpenMP 201511"
+	Features []string
+}
+
+// parseVersionInfo parses the output of tesseract --version
+func parseVersionInfo(out string) (VersionInfo, error) {
+	out = strings.TrimSpace(out)
+	firstLine := strings.SplitN(out, "\n", 2)[0]
+	m := tesseractRegex.FindStringSubmatch(firstLine)
+	if m == nil {
+		return VersionInfo{}, fmt.Errorf("unrecognized tesseract version format: %s", firstLine)
+	}
+	v, err := ParseVersion(m[1])
+	if err != nil {
+		return VersionInfo{}, fmt.Errorf("unrecognized tesseract version format: %s", firstLine)
+	}
+
+	info := VersionInfo{Tesseract: v, Libraries: make(map[string]string)}
+	for _, line := range strings.Split(out, "\n")[1:] {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "leptonica-"):
+			if m := leptonicaRegex.FindStringSubmatch(line); m != nil {
+				info.Leptonica, _ = ParseVersion(m[1])
+			}
+		case strings.HasPrefix(line, "Found lib"):
+			parseLibraries(strings.TrimPrefix(line, "Found "), info.Libraries)
+		case strings.HasPrefix(line, "Found "):
+			info.Features = append(info.Features, strings.TrimPrefix(line, "Found "))
+		default:
+			parseLibraries(line, info.Libraries)
+		}
+	}
+	return info, nil
+}
+
+// parseLibraries reads a library line, either "libpng 1.6.39 : zlib 1.2.13"
+// or "libarchive 3.6.2 zlib/1.2.13 liblzma/5.4.1"
+func parseLibraries(line string, libs map[string]string) {
+	for _, part := range strings.Split(line, " : ") {
+		fields := strings.Fields(part)
+		if len(fields) > 1 && !strings.Contains(part, "/") {
+			libs[fields[0]] = strings.Join(fields[1:], " ")
+			continue
+		}
+		for i := 0; i < len(fields); i++ {
+			if name, version, ok := strings.Cut(fields[i], "/"); ok {
+				libs[name] = version
+			} else if i+1 < len(fields) && !strings.Contains(fields[i+1], "/") {
+				libs[fields[i]] = fields[i+1]
+				i++
+			}
+		}
+	}
+}
+
+// Version returns the version information of the client's tesseract binary
+func (c *Client) Version() (VersionInfo, error) {
+	return c.tesseractVersion()
+}
+
+// requireVersion returns a FeatureError unless the installed tesseract is
+// min or newer
+func (c *Client) requireVersion(feature string, min Version) error {
+	info, err := c.tesseractVersion()
+	if err != nil {
+		return err
+	}
+	if !info.Tesseract.AtLeast(min) {
+		return &FeatureError{Feature: feature, Required: min, Installed: info.Tesseract}
+	}
+	return nil
+}
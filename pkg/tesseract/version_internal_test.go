@@ -0,0 +1,120 @@
+package tesseract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{in: "5.3.0", want: Version{5, 3, 0}},
+		{in: "3.05.02", want: Version{3, 5, 2}},
+		{in: "4.00.00alpha", want: Version{4, 0, 0}},
+		{in: "v5.0.0-alpha-20201231", want: Version{5, 0, 0}},
+		{in: "4.1", want: Version{4, 1, 0}},
+		{in: "5.3.0-12-gabcdef", want: Version{5, 3, 0}},
+		{in: "five", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseVersion(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b Version
+		want int
+	}{
+		{Version{10, 0, 0}, Version{3, 5, 0}, 1},
+		{Version{4, 1, 0}, Version{4, 1, 0}, 0},
+		{Version{4, 0, 9}, Version{4, 1, 0}, -1},
+		{Version{5, 3, 1}, Version{5, 3, 0}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := tt.a.AtLeast(tt.b); got != (tt.want >= 0) {
+			t.Errorf("%v.AtLeast(%v) = %v", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestParseVersionInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want VersionInfo
+	}{
+		{
+			name: "Tesseract 5",
+			out: "tesseract 5.3.0\n leptonica-1.82.0\n" +
+				"  libgif 5.2.1 : libjpeg 8d (libjpeg-turbo 2.1.1) : libpng 1.6.39 : zlib 1.2.13\n" +
+				" Found AVX2\n Found OpenMP 201511\n" +
+				" Found libarchive 3.6.2 zlib/1.2.13 liblzma/5.4.1\n" +
+				" Found libcurl/7.88.1 OpenSSL/3.0.9\n",
+			want: VersionInfo{
+				Tesseract: Version{5, 3, 0},
+				Leptonica: Version{1, 82, 0},
+				Libraries: map[string]string{
+					"libgif": "5.2.1", "libjpeg": "8d (libjpeg-turbo 2.1.1)",
+					"libpng": "1.6.39", "zlib": "1.2.13",
+					"libarchive": "3.6.2", "liblzma": "5.4.1",
+					"libcurl": "7.88.1", "OpenSSL": "3.0.9",
+				},
+				Features: []string{"AVX2", "OpenMP 201511"},
+			},
+		},
+		{
+			name: "Tesseract 3",
+			out:  "tesseract 3.05.02\n leptonica-1.74.4\n  libjpeg 8d : libpng 1.6.34\n",
+			want: VersionInfo{
+				Tesseract: Version{3, 5, 2},
+				Leptonica: Version{1, 74, 4},
+				Libraries: map[string]string{"libjpeg": "8d", "libpng": "1.6.34"},
+			},
+		},
+		{
+			name: "libcurl line",
+			out:  "tesseract 5.3.0\n libcurl/7.81.0 OpenSSL/3.0.2 zlib/1.2.11\n",
+			want: VersionInfo{
+				Tesseract: Version{5, 3, 0},
+				Libraries: map[string]string{"libcurl": "7.81.0", "OpenSSL": "3.0.2", "zlib": "1.2.11"},
+			},
+		},
+		{
+			name: "Bare leptonica line",
+			out:  "tesseract 5.3.0\n leptonica-\n",
+			want: VersionInfo{
+				Tesseract: Version{5, 3, 0},
+				Libraries: map[string]string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVersionInfo(tt.out)
+			if err != nil {
+				t.Fatalf("parseVersionInfo() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVersionInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tesseract_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thedesertm/gotesseract/pkg/tesseract"
+	"github.com/thedesertm/gotesseract/pkg/tesseract/tesseracttest"
+)
+
+func TestFeatureGating(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		run     func(c *tesseract.Client) error
+		wantErr error
+	}{
+		{
+			name:    "ALTO on 4.0",
+			version: "4.0.0",
+			run: func(c *tesseract.Client) error {
+				_, err := c.ImageToALTO(testImage(), "eng")
+				return err
+			},
+			wantErr: tesseract.ErrFeatureUnsupported,
+		},
+		{
+			name:    "ALTO on 4.1",
+			version: "4.1.1",
+			run: func(c *tesseract.Client) error {
+				_, err := c.Recognize(testImage(), "eng", []tesseract.Format{tesseract.FormatALTO})
+				return err
+			},
+		},
+		{
+			name:    "LSTM box on 3.05",
+			version: "3.05.02",
+			run: func(c *tesseract.Client) error {
+				_, err := c.Recognize(testImage(), "eng", []tesseract.Format{tesseract.FormatText, tesseract.FormatLSTMBox})
+				return err
+			},
+			wantErr: tesseract.ErrFeatureUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := tesseracttest.NewExecutor(tt.version, "eng")
+			exec.RespondFunc(renderer(t))
+			client := newClient(t, tesseract.Config{Executor: exec})
+
+			err := tt.run(client)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var fe *tesseract.FeatureError
+			if tt.wantErr != nil && (!errors.As(err, &fe) || fe.Installed.String() == "") {
+				t.Errorf("error = %#v, want *FeatureError", err)
+			}
+		})
+	}
+}
+
+func TestClientVersion(t *testing.T) {
+	client := newClient(t, tesseract.Config{Executor: tesseracttest.NewExecutor("4.1.1")})
+	info, err := client.Version()
+	if err != nil || info.Tesseract != (tesseract.Version{Major: 4, Minor: 1, Patch: 1}) {
+		t.Errorf("Version() = %+v, %v", info, err)
+	}
+}
+
+func TestClientVersionRetry(t *testing.T) {
+	data, err := os.ReadFile(fakeBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tesseract")
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := tesseract.SetTesseractCmd(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := tesseract.SetTesseractCmd("tesseract"); err != nil {
+			t.Errorf("SetTesseractCmd() error = %v", err)
+		}
+	})
+
+	// A failed check is not cached
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tesseract.DefaultClient.Version(); !tesseract.IsNotFound(err) {
+		t.Fatalf("Version() with missing binary error = %v, want IsNotFound", err)
+	}
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	info, err := tesseract.DefaultClient.Version()
+	if err != nil || info.Tesseract != (tesseract.Version{Major: 5, Minor: 3, Patch: 0}) {
+		t.Errorf("Version() after the binary came back = %+v, %v", info, err)
+	}
+}